@@ -0,0 +1,84 @@
+package pretty
+
+import (
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Attribute is a flattened key value pair of a printed value.
+// Value is a bool, int64, float64 or string which are
+// the scalar value types of OpenTelemetry attributes,
+// so an Attribute can be converted to an attribute.KeyValue
+// without adding a dependency on OpenTelemetry to this package:
+//
+//	switch v := a.Value.(type) {
+//	case bool:
+//		kv = attribute.Bool(a.Key, v)
+//	case int64:
+//		kv = attribute.Int64(a.Key, v)
+//	case float64:
+//		kv = attribute.Float64(a.Key, v)
+//	case string:
+//		kv = attribute.String(a.Key, v)
+//	}
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// OTelAttributes flattens value into attributes sorted by key
+// for example to be used as tracing span attributes.
+// The keys are the paths of the leaf values as returned
+// by SprintWithPaths joined to prefix with a dot.
+// The value itself has the key prefix, or "value" if prefix is empty.
+// Values are converted like with ToMap applying the same limits.
+// Nil values and values that are not booleans, numbers or strings
+// are converted to their pretty printed string.
+func (p *Printer) OTelAttributes(value any, prefix string) []Attribute {
+	var attrs []Attribute
+	p.walk(reflect.ValueOf(value), "", printState{ptrs: make(visitedPtrs)}, func(path string, v reflect.Value, s printState, leaf bool) bool {
+		if !leaf {
+			return true
+		}
+		key := joinPath(prefix, path)
+		switch {
+		case path == "" && prefix == "":
+			key = "value"
+		case path == "":
+			key = prefix
+		}
+		attrs = append(attrs, Attribute{Key: key, Value: p.attributeValue(v, s)})
+		return false
+	})
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
+
+// attributeValue returns the leaf value v
+// as bool, int64, float64 or string.
+func (p *Printer) attributeValue(v reflect.Value, s printState) any {
+	x := p.toMapLeaf(v, s)
+	if x == nil {
+		if isNullValue(v) {
+			return p.nullToken()
+		}
+		return p.nilToken()
+	}
+	xv := reflect.ValueOf(x)
+	switch xv.Kind() {
+	case reflect.Bool:
+		return xv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return xv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if xv.Uint() <= math.MaxInt64 {
+			return int64(xv.Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return xv.Float()
+	case reflect.String:
+		return xv.String()
+	}
+	return p.sprintState(v, s)
+}
@@ -0,0 +1,122 @@
+// Package bench provides a corpus of representative values
+// and helpers to compare the output and allocations
+// of differently configured pretty.Printer instances.
+package bench
+
+import (
+	"fmt"
+	"testing"
+
+	pretty "github.com/domonda/go-pretty"
+)
+
+// Case is a named value of the corpus
+type Case struct {
+	Name  string
+	Value any
+}
+
+// Node is used for nested and cyclic corpus values
+type Node struct {
+	ID       int
+	Name     string
+	Parent   *Node
+	Children []*Node
+	Attrs    map[string]any
+}
+
+// Corpus returns new instances of representative values
+// with deep nesting, wide maps, binary slices and cyclic graphs.
+func Corpus() []Case {
+	deep := &Node{ID: 0, Name: "root"}
+	for n, i := deep, 1; i < 50; i++ {
+		child := &Node{ID: i, Name: fmt.Sprintf("level-%d", i)}
+		n.Children = []*Node{child}
+		n = child
+	}
+
+	wide := make(map[string]int, 1000)
+	for i := 0; i < 1000; i++ {
+		wide[fmt.Sprintf("key-%04d", i)] = i
+	}
+
+	binary := make([]byte, 64*1024)
+	for i := range binary {
+		binary[i] = byte(i)
+	}
+
+	cyclic := &Node{ID: 0, Name: "cyclic", Attrs: map[string]any{"kind": "graph"}}
+	for i := 1; i <= 10; i++ {
+		child := &Node{ID: i, Name: fmt.Sprintf("child-%d", i), Parent: cyclic}
+		cyclic.Children = append(cyclic.Children, child)
+	}
+	cyclic.Attrs["self"] = cyclic
+
+	return []Case{
+		{Name: "DeepNesting", Value: deep},
+		{Name: "WideMap", Value: wide},
+		{Name: "BinarySlice", Value: binary},
+		{Name: "CyclicGraph", Value: cyclic},
+		{Name: "Strings", Value: []string{"Hello", "World\n", "`quoted`", ""}},
+	}
+}
+
+// Result of printing a value with a Printer
+type Result struct {
+	Output string
+	// AllocsPerRun is the average number of
+	// allocations for printing the value once
+	AllocsPerRun float64
+}
+
+// Measure prints value with p and measures the allocations.
+func Measure(p *pretty.Printer, value any) Result {
+	return Result{
+		Output:       p.Sprint(value),
+		AllocsPerRun: testing.AllocsPerRun(10, func() { p.Sprint(value) }),
+	}
+}
+
+// Comparison of the results of two printers for a Case
+type Comparison struct {
+	Name  string
+	Base  Result
+	Other Result
+}
+
+// SameOutput returns if both printers produced the same output
+func (c *Comparison) SameOutput() bool {
+	return c.Base.Output == c.Other.Output
+}
+
+// AllocsRegressed returns if the other printer
+// allocated more often than the base printer.
+func (c *Comparison) AllocsRegressed() bool {
+	return c.Other.AllocsPerRun > c.Base.AllocsPerRun
+}
+
+// Compare measures all cases with the base and other printer.
+func Compare(base, other *pretty.Printer, cases []Case) []Comparison {
+	comparisons := make([]Comparison, len(cases))
+	for i, c := range cases {
+		comparisons[i] = Comparison{
+			Name:  c.Name,
+			Base:  Measure(base, c.Value),
+			Other: Measure(other, c.Value),
+		}
+	}
+	return comparisons
+}
+
+// Run runs a sub-benchmark for every case printed with p.
+func Run(b *testing.B, p *pretty.Printer, cases []Case) {
+	for _, c := range cases {
+		value := c.Value
+		b.Run(c.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p.Sprint(value)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package bench
+
+import (
+	"testing"
+
+	pretty "github.com/domonda/go-pretty"
+)
+
+func TestCompare(t *testing.T) {
+	base := pretty.DefaultPrinter
+	other := pretty.DefaultPrinter
+	for _, c := range Compare(&base, &other, Corpus()) {
+		if !c.SameOutput() {
+			t.Errorf("%s: different output for same Printer configuration", c.Name)
+		}
+		if c.Base.Output == "" {
+			t.Errorf("%s: empty output", c.Name)
+		}
+	}
+}
+
+func BenchmarkDefaultPrinter(b *testing.B) {
+	Run(b, &pretty.DefaultPrinter, Corpus())
+}
@@ -0,0 +1,46 @@
+package pretty
+
+import (
+	"io"
+	"math/big"
+	"reflect"
+)
+
+var (
+	typeOfBigInt   = reflect.TypeOf(big.Int{})
+	typeOfBigFloat = reflect.TypeOf(big.Float{})
+	typeOfBigRat   = reflect.TypeOf(big.Rat{})
+)
+
+// isBigNumberType returns if t is big.Int, big.Float or big.Rat
+func isBigNumberType(t reflect.Type) bool {
+	return t == typeOfBigInt || t == typeOfBigFloat || t == typeOfBigRat
+}
+
+// fprintBigNumber prints a big.Int, big.Float or big.Rat value
+// as decimal string like BigInt(123456789) truncated
+// to MaxStringLength instead of its internal words.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintBigNumber(w io.Writer, v reflect.Value, s printState) {
+	if !v.CanAddr() {
+		// The methods have pointer receivers
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+	var name, str string
+	switch x := v.Addr().Interface().(type) {
+	case *big.Int:
+		name, str = "BigInt", x.String()
+	case *big.Float:
+		name, str = "BigFloat", x.Text('g', -1)
+	case *big.Rat:
+		name, str = "BigRat", x.RatString()
+	}
+	if maxLen := s.limit(p.MaxStringLength); maxLen > 0 && len(str) > maxLen {
+		s.truncated(len(str), maxLen)
+		str = str[:maxLen] + p.ellipsis()
+	}
+	io.WriteString(w, name+"("+str+")")
+}
@@ -0,0 +1,65 @@
+package pretty
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// RegisterBitmask registers names for the bits of the integer type t
+// so that values of t are printed like Flags(READ|WRITE|0x40)
+// with the remaining unnamed bits in hex.
+// The keys of names can also be masks of multiple bits.
+// RegisterBitmask panics if t is not an integer type.
+// Not safe for concurrent use with printing.
+func (p *Printer) RegisterBitmask(t reflect.Type, names map[uint64]string) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		panic("RegisterBitmask called with non integer type " + t.String())
+	}
+	bits := make([]bitName, 0, len(names))
+	for mask, name := range names {
+		if mask != 0 {
+			bits = append(bits, bitName{mask: mask, name: name})
+		}
+	}
+	sort.Slice(bits, func(i, j int) bool { return bits[i].mask < bits[j].mask })
+	if p.bitmasks == nil {
+		p.bitmasks = make(map[reflect.Type][]bitName)
+	}
+	p.bitmasks[t] = bits
+}
+
+type bitName struct {
+	mask uint64
+	name string
+}
+
+// sprintBitmask returns the value of the integer v
+// formatted with the registered bit names.
+func sprintBitmask(v reflect.Value, bits []bitName) string {
+	var value uint64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = uint64(v.Int())
+	default:
+		value = v.Uint()
+	}
+	if value == 0 {
+		return v.Type().Name() + "(0)"
+	}
+	var names []string
+	for _, bit := range bits {
+		if value&bit.mask == bit.mask {
+			names = append(names, bit.name)
+			value &^= bit.mask
+		}
+	}
+	if value != 0 {
+		names = append(names, fmt.Sprintf("%#x", value))
+	}
+	return v.Type().Name() + "(" + strings.Join(names, "|") + ")"
+}
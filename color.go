@@ -0,0 +1,212 @@
+package pretty
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ColorScheme defines the ANSI escape sequences used
+// to highlight the tokens of the output with Printer.Colors.
+// An empty sequence leaves the tokens of that kind uncolored.
+type ColorScheme struct {
+	// TypeName like Struct in Struct{…} or Time in Time(…)
+	TypeName string
+	// FieldName of structs like Name in Name:`x`
+	FieldName string
+	// String literals including map keys
+	String string
+	// Number and boolean literals
+	Number string
+	// Nil is used for the nil, null and circular reference tokens
+	Nil string
+	// Error is used for the error token of error(…)
+	Error string
+}
+
+// ANSI escape sequences for common terminal colors
+const (
+	ColorReset   = "\x1b[0m"
+	ColorRed     = "\x1b[31m"
+	ColorGreen   = "\x1b[32m"
+	ColorYellow  = "\x1b[33m"
+	ColorBlue    = "\x1b[34m"
+	ColorMagenta = "\x1b[35m"
+	ColorCyan    = "\x1b[36m"
+	ColorGray    = "\x1b[90m"
+	ColorBoldRed = "\x1b[1;31m"
+)
+
+// DefaultColorScheme is a ColorScheme for terminals with dark
+// and light backgrounds that can be used for Printer.Colors.
+var DefaultColorScheme = ColorScheme{
+	TypeName:  ColorCyan,
+	FieldName: ColorBlue,
+	String:    ColorGreen,
+	Number:    ColorYellow,
+	Nil:       ColorMagenta,
+	Error:     ColorBoldRed,
+}
+
+// useColors returns if the output written to w
+// should be highlighted with p.Colors
+func (p *Printer) useColors(w io.Writer) bool {
+	switch {
+	case p.Colors == nil || p.DisableColor:
+		return false
+	case p.ForceColor:
+		return true
+	case os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb":
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal returns if w is a file of a character device
+// like a terminal and not a regular file or pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize returns the pretty printed source
+// with its tokens highlighted with p.Colors
+func (p *Printer) colorize(source []byte) []byte {
+	var (
+		colors = p.Colors
+		result = make([]byte, 0, len(source)*2)
+		tokens = []string{p.nilToken(), p.nullToken(), p.circularRefToken()}
+	)
+	appendColored := func(color string, token []byte) {
+		if color == "" {
+			result = append(result, token...)
+			return
+		}
+		result = append(result, color...)
+		result = append(result, token...)
+		result = append(result, ColorReset...)
+	}
+	atBoundary := func(i int) bool {
+		if i == 0 {
+			return true
+		}
+		r, _ := utf8.DecodeLastRune(source[:i])
+		return !isIdentRune(r)
+	}
+
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == '`':
+			end := bytes.IndexByte(source[i+1:], '`')
+			if end < 0 {
+				end = len(source)
+			} else {
+				end += i + 2
+			}
+			appendColored(colors.String, source[i:end])
+			i = end
+			continue
+
+		case c == '"':
+			end := i + 1
+			for end < len(source) && source[end] != '"' {
+				if source[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(source) {
+				end++
+			} else {
+				end = len(source)
+			}
+			appendColored(colors.String, source[i:end])
+			i = end
+			continue
+		}
+
+		if atBoundary(i) {
+			if token := matchToken(source[i:], tokens); token > 0 {
+				appendColored(colors.Nil, source[i:i+token])
+				i += token
+				continue
+			}
+			if c >= '0' && c <= '9' || (c == '-' || c == '+') && i+1 < len(source) && source[i+1] >= '0' && source[i+1] <= '9' {
+				end := i + 1
+				for end < len(source) && isNumberByte(source[end]) {
+					end++
+				}
+				appendColored(colors.Number, source[i:end])
+				i = end
+				continue
+			}
+			if r, _ := utf8.DecodeRune(source[i:]); unicode.IsLetter(r) || r == '_' {
+				end := i
+				for end < len(source) {
+					r, size := utf8.DecodeRune(source[end:])
+					if !isIdentRune(r) && r != '.' {
+						break
+					}
+					end += size
+				}
+				ident := source[i:end]
+				var next byte
+				if end < len(source) {
+					next = source[end]
+				}
+				switch {
+				case string(ident) == "error" && next == '(':
+					appendColored(colors.Error, ident)
+				case next == '{' || next == '(' || next == p.openBracket():
+					appendColored(colors.TypeName, ident)
+				case next == ':':
+					appendColored(colors.FieldName, ident)
+				case string(ident) == "true" || string(ident) == "false" || string(ident) == "NaN":
+					appendColored(colors.Number, ident)
+				default:
+					result = append(result, ident...)
+				}
+				i = end
+				continue
+			}
+		}
+		result = append(result, c)
+		i++
+	}
+	return result
+}
+
+// openBracket returns the first byte of the opening bracket
+func (p *Printer) openBracket() byte {
+	open, _ := p.brackets()
+	return open[0]
+}
+
+// matchToken returns the length of the first of tokens
+// that source starts with followed by a non identifier rune
+func matchToken(source []byte, tokens []string) int {
+	for _, token := range tokens {
+		if token == "" || !bytes.HasPrefix(source, []byte(token)) {
+			continue
+		}
+		if r, _ := utf8.DecodeRune(source[len(token):]); len(source) == len(token) || !isIdentRune(r) {
+			return len(token)
+		}
+	}
+	return 0
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNumberByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '.' || c == '_' || c == '+' || c == '-'
+}
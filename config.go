@@ -1,24 +1,85 @@
 package pretty
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sync/atomic"
 	"time"
 )
 
+// Default limits of DefaultPrinter
+const (
+	DefaultMaxStringLength = 200
+	DefaultMaxErrorLength  = 2000
+	DefaultMaxSliceLength  = 20
+)
+
+// DefaultLineWidth is the line width used by Printer.FitLineWidth
+// if neither MaxLineWidth nor a terminal width is available.
+const DefaultLineWidth = 80
+
 // DefaultPrinter is used by the package level print functions
+// until SetDefault or ResetDefaults is called.
+// After that, changes to DefaultPrinter have no effect.
+//
+// Deprecated: Modifying DefaultPrinter is not safe
+// for concurrent use with printing, use SetDefault instead.
 var DefaultPrinter = Printer{
-	MaxStringLength: 200,
-	MaxErrorLength:  2000,
-	MaxSliceLength:  20,
+	MaxStringLength: DefaultMaxStringLength,
+	MaxErrorLength:  DefaultMaxErrorLength,
+	MaxSliceLength:  DefaultMaxSliceLength,
+}
+
+// defaultPrinter holds the *Printer returned by Default
+var defaultPrinter atomic.Value
+
+func init() {
+	defaultPrinter.Store(&DefaultPrinter)
+}
+
+// Default returns the Printer used by the package level print functions.
+// The returned Printer must not be modified,
+// use Printer.With to derive a modified copy.
+func Default() *Printer {
+	return defaultPrinter.Load().(*Printer)
+}
+
+// SetDefault atomically replaces the Printer used
+// by the package level print functions with a copy of p.
+// Safe for concurrent use with printing.
+func SetDefault(p Printer) {
+	defaultPrinter.Store(p.With())
+}
+
+// ResetDefaults atomically replaces the Printer used
+// by the package level print functions with a new Printer
+// with the initial configuration of DefaultPrinter.
+// DefaultPrinter itself is not modified.
+// Useful for tests that modify the default configuration.
+// Safe for concurrent use with printing.
+func ResetDefaults() {
+	defaultPrinter.Store(&Printer{
+		MaxStringLength: DefaultMaxStringLength,
+		MaxErrorLength:  DefaultMaxErrorLength,
+		MaxSliceLength:  DefaultMaxSliceLength,
+	})
 }
 
 // CircularRef is a replacement token CIRCULAR_REF
-// that will be printed instad of a circular data reference.
+// that will be printed instad of a circular data reference
+// if Printer.CircularRefToken is empty.
 const CircularRef = "CIRCULAR_REF"
 
+// Redacted replaces the matches of Printer.RedactPatterns
+const Redacted = "REDACTED"
+
 var (
 	typeOfByte     = reflect.TypeOf(byte(0))
 	typeOfRune     = reflect.TypeOf(rune(0))
 	typeOfTime     = reflect.TypeOf(time.Time{})
 	typeOfDuration = reflect.TypeOf(time.Duration(0))
+	typeOfCancel   = reflect.TypeOf(context.CancelFunc(nil))
+	typeOfError    = reflect.TypeOf((*error)(nil)).Elem()
+	typeOfStringer = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 )
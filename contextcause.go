@@ -0,0 +1,18 @@
+//go:build go1.20
+
+package pretty
+
+import (
+	"context"
+	"reflect"
+)
+
+// typeOfCancelCause is the type context.CancelCauseFunc
+// which is available since Go 1.20.
+var typeOfCancelCause = reflect.TypeOf(context.CancelCauseFunc(nil))
+
+// contextCause returns context.Cause(ctx)
+// which is available since Go 1.20.
+func contextCause(ctx context.Context) error {
+	return context.Cause(ctx)
+}
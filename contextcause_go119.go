@@ -0,0 +1,18 @@
+//go:build !go1.20
+
+package pretty
+
+import (
+	"context"
+	"reflect"
+)
+
+// typeOfCancelCause is nil because
+// context.CancelCauseFunc is not available before Go 1.20.
+var typeOfCancelCause reflect.Type
+
+// contextCause returns nil because
+// context.Cause is not available before Go 1.20.
+func contextCause(ctx context.Context) error {
+	return nil
+}
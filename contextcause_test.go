@@ -0,0 +1,48 @@
+//go:build go1.20
+
+package pretty
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContextCause(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("shutdown requested"))
+	if got, want := Sprint(ctx), "Context{Err:`context canceled`;Cause:`shutdown requested`}"; got != want {
+		t.Errorf("Sprint() = %s, want %s", got, want)
+	}
+
+	// Without an explicit cause the cause equals Err
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	cancelFunc()
+	if got, want := Sprint(ctx), "Context{Err:`context canceled`}"; got != want {
+		t.Errorf("Sprint() = %s, want %s", got, want)
+	}
+}
+
+func TestCancelCauseFunc(t *testing.T) {
+	type Worker struct {
+		Cancel context.CancelCauseFunc
+	}
+	_, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "CancelCauseFunc", value: cancel, want: "CancelCauseFunc"},
+		{name: "nil CancelCauseFunc", value: context.CancelCauseFunc(nil), want: "nil"},
+		{name: "Worker", value: Worker{Cancel: cancel}, want: "Worker{Cancel:CancelCauseFunc}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sprint(tt.value); got != tt.want {
+				t.Errorf("Sprint() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package pretty
+
+import "reflect"
+
+// mapOverhead is the estimated memory overhead of a map
+// in addition to the size of its keys and values
+const mapOverhead = 48
+
+// DeepSize estimates the memory footprint of value in bytes
+// including all memory referenced by pointers, slices,
+// strings, maps, channels and interfaces.
+// Memory referenced multiple times by pointers, slices
+// or maps is only counted once, which also handles
+// circular references.
+// Unexported fields are included.
+// The result is an estimate because allocation size classes,
+// map buckets and shared string data are not taken into account.
+func DeepSize(value any) int64 {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return 0
+	}
+	return int64(v.Type().Size()) + referencedSize(v, make(visitedPtrs))
+}
+
+// referencedSize returns the size of the memory referenced by v
+// without the size of v itself.
+func referencedSize(v reflect.Value, visited visitedPtrs) int64 {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || visited.visit(v.Pointer(), "") {
+			return 0
+		}
+		return int64(v.Type().Elem().Size()) + referencedSize(v.Elem(), visited)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		e := v.Elem()
+		switch e.Kind() {
+		case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+			// Pointer shaped values are stored directly in the interface
+			return referencedSize(e, visited)
+		}
+		return int64(e.Type().Size()) + referencedSize(e, visited)
+
+	case reflect.String:
+		return int64(v.Len())
+
+	case reflect.Slice:
+		if v.IsNil() || visited.visit(v.Pointer(), "") {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		if hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += referencedSize(v.Index(i), visited)
+			}
+		}
+		return size
+
+	case reflect.Array:
+		var size int64
+		if hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += referencedSize(v.Index(i), visited)
+			}
+		}
+		return size
+
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += referencedSize(v.Field(i), visited)
+		}
+		return size
+
+	case reflect.Map:
+		if v.IsNil() || visited.visit(v.Pointer(), "") {
+			return 0
+		}
+		t := v.Type()
+		size := mapOverhead + int64(v.Len())*int64(t.Key().Size()+t.Elem().Size())
+		if hasReferences(t.Key()) || hasReferences(t.Elem()) {
+			iter := v.MapRange()
+			for iter.Next() {
+				size += referencedSize(iter.Key(), visited)
+				size += referencedSize(iter.Value(), visited)
+			}
+		}
+		return size
+
+	case reflect.Chan:
+		if v.IsNil() || visited.visit(v.Pointer(), "") {
+			return 0
+		}
+		return int64(v.Cap()) * int64(v.Type().Elem().Size())
+	}
+	return 0
+}
+
+// hasReferences returns if values of type t
+// can reference memory that is counted by referencedSize.
+func hasReferences(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.String, reflect.Slice, reflect.Map, reflect.Chan:
+		return true
+	case reflect.Array:
+		return hasReferences(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasReferences(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
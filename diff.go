@@ -0,0 +1,210 @@
+package pretty
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DiffJSON unmarshals the JSON documents a and b
+// and returns the structural differences as lines
+// of pretty printed values sorted by path.
+// Removed values are prefixed with "- ", added values with "+ "
+// and changed values are printed like "~ path: old → new".
+// An empty string is returned if there are no differences.
+func (p *Printer) DiffJSON(a, b []byte) (string, error) {
+	var aVal, bVal any
+	if err := json.Unmarshal(a, &aVal); err != nil {
+		return "", fmt.Errorf("can't unmarshal JSON a: %w", err)
+	}
+	if err := json.Unmarshal(b, &bVal); err != nil {
+		return "", fmt.Errorf("can't unmarshal JSON b: %w", err)
+	}
+	return p.diff(aVal, bVal), nil
+}
+
+// DiffMaps returns the added, removed and changed keys
+// of the maps a and b as lines of pretty printed values
+// sorted by key in the same format as DiffJSON.
+// Nested values are compared by their paths.
+// An empty string is returned if there are no differences.
+func (p *Printer) DiffMaps(a, b map[string]any) string {
+	if a == nil {
+		a = map[string]any{}
+	}
+	if b == nil {
+		b = map[string]any{}
+	}
+	return p.diff(a, b)
+}
+
+// diff returns the differences between the leaf values
+// of a and b as returned by SprintWithPaths.
+// Slices and maps are compared with all elements.
+func (p *Printer) diff(a, b any) string {
+	return formatPathDiff(p.unlimitedPaths(a), p.unlimitedPaths(b), p.arrow())
+}
+
+// pathValue is a leaf value of a diff
+type pathValue struct {
+	// str is the pretty printed value
+	str string
+	// emptyContainer is true for an empty or nil
+	// slice, array or map that may have nested paths
+	// in the value compared with
+	emptyContainer bool
+}
+
+// unlimitedPaths returns the leaf values of value
+// with the paths of SprintWithPaths without truncating
+// any strings, errors, slices, maps, structs or nesting depth,
+// so that differences after the limits are not lost.
+func (p *Printer) unlimitedPaths(value any) map[string]pathValue {
+	unlimited := *p
+	unlimited.renderCache = nil
+	unlimited.MaxStringLength = 0
+	unlimited.MaxErrorLength = 0
+	unlimited.MaxSliceLength = 0
+	unlimited.MaxMapLength = 0
+	unlimited.MaxStructFields = 0
+	unlimited.MaxDepth = 0
+	unlimited.MaxTotalLength = 0
+
+	paths := make(map[string]pathValue)
+	unlimited.walk(reflect.ValueOf(value), "", printState{ptrs: make(visitedPtrs)}, func(path string, v reflect.Value, s printState, leaf bool) bool {
+		if !leaf {
+			return true
+		}
+		if !v.IsValid() {
+			paths[path] = pathValue{str: unlimited.nilToken()}
+			return false
+		}
+		var b strings.Builder
+		unlimited.fprint(&b, v, s)
+		paths[path] = pathValue{str: b.String(), emptyContainer: isEmptyContainer(v)}
+		return false
+	})
+	return paths
+}
+
+// isEmptyContainer returns if v is an empty or nil
+// slice, array or map, or a pointer or interface to one
+func isEmptyContainer(v reflect.Value) bool {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// DiffIgnoring returns the differences between the leaf values
+// of a and b in the same format as DiffJSON
+// skipping the paths matching any of the ignore paths.
+// An ignore path matches a path of SprintWithPaths
+// and all paths nested in it, either from the root
+// or starting after any dot of the path,
+// so "ID" matches "ID", "Owner.ID" and "Items[0].ID",
+// and "Items[0]" matches "Items[0].Name".
+// An empty string is returned if there are no differences.
+func (p *Printer) DiffIgnoring(a, b any, ignore ...string) string {
+	aPaths := p.unlimitedPaths(a)
+	bPaths := p.unlimitedPaths(b)
+	for _, paths := range []map[string]pathValue{aPaths, bPaths} {
+		for path := range paths {
+			if isIgnoredPath(path, ignore) {
+				delete(paths, path)
+			}
+		}
+	}
+	return formatPathDiff(aPaths, bPaths, p.arrow())
+}
+
+// EqualIgnoring returns true if a and b have
+// no differences as returned by DiffIgnoring,
+// for example to compare entities ignoring
+// volatile fields like "ID" or "CreatedAt".
+func (p *Printer) EqualIgnoring(a, b any, ignore ...string) bool {
+	return p.DiffIgnoring(a, b, ignore...) == ""
+}
+
+// isIgnoredPath returns if path matches
+// one of the ignore paths as described at DiffIgnoring.
+func isIgnoredPath(path string, ignore []string) bool {
+	for _, ig := range ignore {
+		if ig == "" {
+			continue
+		}
+		for sub := path; ; {
+			if strings.HasPrefix(sub, ig) && (len(sub) == len(ig) || sub[len(ig)] == '.' || sub[len(ig)] == '[') {
+				return true
+			}
+			dot := strings.IndexByte(sub, '.')
+			if dot < 0 {
+				break
+			}
+			sub = sub[dot+1:]
+		}
+	}
+	return false
+}
+
+// formatPathDiff formats the differences between
+// the path to value maps a and b
+// with arrow between changed values.
+func formatPathDiff(a, b map[string]pathValue, arrow string) string {
+	paths := make([]string, 0, len(a)+len(b))
+	for path := range a {
+		paths = append(paths, path)
+	}
+	for path := range b {
+		if _, ok := a[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var result strings.Builder
+	for _, path := range paths {
+		aVal, aOK := a[path]
+		bVal, bOK := b[path]
+		displayPath := path
+		if displayPath == "" {
+			displayPath = "."
+		}
+		switch {
+		case !bOK:
+			if aVal.emptyContainer && hasChildPath(b, path) {
+				// Empty container in a got elements in b
+				continue
+			}
+			fmt.Fprintf(&result, "- %s: %s\n", displayPath, aVal.str)
+		case !aOK:
+			if bVal.emptyContainer && hasChildPath(a, path) {
+				// Empty container in b had elements in a
+				continue
+			}
+			fmt.Fprintf(&result, "+ %s: %s\n", displayPath, bVal.str)
+		case aVal.str != bVal.str:
+			fmt.Fprintf(&result, "~ %s: %s %s %s\n", displayPath, aVal.str, arrow, bVal.str)
+		}
+	}
+	return result.String()
+}
+
+// hasChildPath returns if paths contains a path nested in parent
+func hasChildPath(paths map[string]pathValue, parent string) bool {
+	for path := range paths {
+		if len(path) <= len(parent) || !strings.HasPrefix(path, parent) {
+			continue
+		}
+		if parent == "" || path[len(parent)] == '.' || path[len(parent)] == '[' {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package pretty
+
+import (
+	"io"
+	"reflect"
+)
+
+// FprintEach pretty prints every element of a slice or array
+// without the surrounding brackets to w, separated by sep.
+// If sep is empty, then every element is printed
+// on its own line ending with a newline.
+// Elements are not limited by MaxSliceLength.
+// Other values are printed like a slice with a single element.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) FprintEach(w io.Writer, slice any, sep string) {
+	v := reflect.ValueOf(slice)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if sep == "" {
+			p.Fprintln(w, slice)
+		} else {
+			p.Fprint(w, slice)
+		}
+		return
+	}
+	for i := 0; i < v.Len(); i++ {
+		if sep != "" && i > 0 {
+			io.WriteString(w, sep)
+		}
+		p.fprintIndent(w, v.Index(i).Interface(), nil)
+		if sep == "" {
+			io.WriteString(w, "\n")
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package pretty
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Fixed limits of Emergency
+const (
+	emergencyMaxDepth     = 5
+	emergencyMaxString    = 200
+	emergencyMaxElems     = 20
+	emergencyMaxOutputLen = 4096
+)
+
+// Emergency prints value to os.Stderr followed by a newline
+// for last-gasp dumps inside panic handlers and signal hooks.
+// No methods of the value like PrettyPrint, String or Error are called,
+// map keys are not sorted, the output is limited to 4096 bytes
+// with strict limits for depth, strings and elements
+// and panics of the reflection itself are recovered.
+// Circular references are not detected but bounded by the depth limit.
+func Emergency(value any) {
+	var buf [emergencyMaxOutputLen + 1]byte
+	b := fprintEmergency(buf[:0], value)
+	b = append(b, '\n')
+	os.Stderr.Write(b) //#nosec G104
+}
+
+// fprintEmergency appends the emergency representation of value to b.
+func fprintEmergency(b []byte, value any) (result []byte) {
+	defer func() {
+		if recover() != nil {
+			result = append(b, "PANIC"...)
+		}
+	}()
+	b = appendEmergency(b, reflect.ValueOf(value), 0)
+	if len(b) > emergencyMaxOutputLen {
+		// Cut at the start of a rune to keep valid UTF-8
+		n := emergencyMaxOutputLen - len("…")
+		for n > 0 && !utf8.RuneStart(b[n]) {
+			n--
+		}
+		b = append(b[:n], "…"...)
+	}
+	return b
+}
+
+func appendEmergency(b []byte, v reflect.Value, depth int) []byte {
+	if len(b) > emergencyMaxOutputLen {
+		return b
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(b, "nil"...)
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return append(b, "nil"...)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(b, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(b, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(b, v.Uint(), 10)
+	case reflect.Uintptr:
+		return strconv.AppendUint(append(b, "0x"...), v.Uint(), 16)
+	case reflect.Float32:
+		return strconv.AppendFloat(b, v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.AppendFloat(b, v.Float(), 'g', -1, 64)
+	case reflect.String:
+		return appendEmergencyString(b, v.String())
+	case reflect.Struct:
+		b = append(b, v.Type().Name()...)
+		if depth >= emergencyMaxDepth {
+			return append(b, "{…}"...)
+		}
+		b = append(b, '{')
+		t := v.Type()
+		n := 0
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if n > 0 {
+				b = append(b, ';')
+			}
+			if n == emergencyMaxElems {
+				b = append(b, "…"...)
+				break
+			}
+			b = append(b, t.Field(i).Name...)
+			b = append(b, ':')
+			b = appendEmergency(b, v.Field(i), depth+1)
+			n++
+		}
+		return append(b, '}')
+	case reflect.Map:
+		if v.IsNil() {
+			return append(b, "nil"...)
+		}
+		if depth >= emergencyMaxDepth {
+			return append(b, "{…}"...)
+		}
+		b = append(b, '{')
+		iter := v.MapRange()
+		for n := 0; iter.Next(); n++ {
+			if n > 0 {
+				b = append(b, ';')
+			}
+			if n == emergencyMaxElems {
+				b = append(b, "…"...)
+				break
+			}
+			b = appendEmergency(b, iter.Key(), depth+1)
+			b = append(b, ':')
+			b = appendEmergency(b, iter.Value(), depth+1)
+		}
+		return append(b, '}')
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(b, "nil"...)
+		}
+		if depth >= emergencyMaxDepth {
+			return append(b, "[…]"...)
+		}
+		b = append(b, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if i == emergencyMaxElems {
+				b = append(b, "…"...)
+				break
+			}
+			b = appendEmergency(b, v.Index(i), depth+1)
+		}
+		return append(b, ']')
+	default:
+		// Complex numbers, channels, functions and unsafe pointers
+		return append(b, v.Type().String()...)
+	}
+}
+
+// appendEmergencyString appends s in backticks
+// with invalid UTF-8 and control characters escaped
+// without calling fmt.
+func appendEmergencyString(b []byte, s string) []byte {
+	b = append(b, '`')
+	for i, r := range s {
+		if i >= emergencyMaxString {
+			b = append(b, "…"...)
+			break
+		}
+		switch {
+		case r == utf8.RuneError:
+			b = append(b, `�`...)
+		case r < ' ' || r == '`' || r == 0x7f:
+			b = append(b, `\x`...)
+			b = append(b, "0123456789abcdef"[r>>4], "0123456789abcdef"[r&0xf])
+		default:
+			b = utf8.AppendRune(b, r)
+		}
+	}
+	return append(b, '`')
+}
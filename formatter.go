@@ -0,0 +1,72 @@
+package pretty
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// globalFormatters maps reflect.Type to func(any) string
+	globalFormatters sync.Map
+	// numGlobalFormatters is used to skip the lookup
+	// in globalFormatters if no formatters are registered
+	numGlobalFormatters int32
+)
+
+// RegisterFormatter registers a function that returns
+// the printed representation of values of type T
+// for all Printers that don't have their own formatter
+// registered for T with Printer.RegisterFormatter.
+// This allows to override the printing of types
+// like UUIDs, decimals or domain IDs without
+// implementing Printable for them.
+// The result of format is printed as is without quoting.
+// Nil pointers and interfaces are printed as nil
+// without calling format.
+// Safe for concurrent use.
+func RegisterFormatter[T any](format func(T) string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	f := func(v any) string { return format(v.(T)) }
+	if _, loaded := globalFormatters.LoadOrStore(t, f); loaded {
+		globalFormatters.Store(t, f)
+	} else {
+		atomic.AddInt32(&numGlobalFormatters, 1)
+	}
+}
+
+// RegisterFormatter registers a function that returns the printed
+// representation of values of type t for this Printer,
+// see the package level RegisterFormatter function.
+// The value passed to format is always of type t
+// and never a nil pointer or interface.
+// Not safe for concurrent use with printing.
+func (p *Printer) RegisterFormatter(t reflect.Type, format func(v any) string) {
+	if p.formatters == nil {
+		p.formatters = make(map[reflect.Type]func(any) string)
+	}
+	p.formatters[t] = format
+}
+
+// valueFormatter returns the formatter registered for the type of v
+// or nil if there is none or v is invalid or a nil pointer or interface
+func (p *Printer) valueFormatter(v reflect.Value) func(any) string {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil
+	}
+	return p.formatter(v.Type())
+}
+
+// formatter returns the formatter registered for t or nil
+func (p *Printer) formatter(t reflect.Type) func(any) string {
+	if format, ok := p.formatters[t]; ok {
+		return format
+	}
+	if atomic.LoadInt32(&numGlobalFormatters) == 0 {
+		return nil
+	}
+	if format, ok := globalFormatters.Load(t); ok {
+		return format.(func(any) string)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+package pretty
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SprintGo returns value as Go expression
+// using the default Printer, see Printer.SprintGo.
+func SprintGo(value any) string {
+	return Default().SprintGo(value)
+}
+
+// SprintGo returns value as compilable Go expression
+// like &pkg.Struct{Int: 1, Str: "x"} on a single line,
+// for example to copy live values into test fixtures.
+// Type names are qualified with their package name
+// as returned by reflect.Type.String,
+// so the code using the expression has to import the packages.
+// Zero and unexported struct fields are omitted,
+// map keys are sorted, time.Time values are written
+// as time.Date calls and errors as errors.New calls.
+// Limits like MaxStringLength are not applied.
+// Values that can't be expressed like non nil
+// functions, channels or circular references
+// are written as nil followed by a comment.
+func (p *Printer) SprintGo(value any) string {
+	var b strings.Builder
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return "nil"
+	}
+	p.writeGo(&b, v, nil, false, p.newPrintState())
+	return b.String()
+}
+
+var (
+	goDefaultTypes = map[reflect.Type]bool{
+		reflect.TypeOf(false):         true,
+		reflect.TypeOf(0):             true,
+		reflect.TypeOf(0.0):           true,
+		reflect.TypeOf(""):            true,
+		reflect.TypeOf(complex128(0)): true,
+	}
+	goDurationUnits = []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+)
+
+// writeGo writes v as Go expression to b.
+// static is the type of the context like a struct field
+// or nil for untyped contexts like interfaces.
+// If elide is true, then the type of a composite literal
+// can be elided like for elements of slices and maps.
+func (p *Printer) writeGo(b *strings.Builder, v reflect.Value, static reflect.Type, elide bool, s printState) {
+	if static != nil && static.Kind() == reflect.Interface {
+		if v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				b.WriteString("nil")
+				return
+			}
+			v = v.Elem()
+		}
+		static = nil
+	}
+	t := v.Type()
+	// convert wraps the literal lit in a conversion to t
+	// if the context doesn't already have the type t
+	convert := func(lit string) {
+		if static == t || static == nil && goDefaultTypes[t] {
+			b.WriteString(lit)
+			return
+		}
+		b.WriteString(goTypeName(t) + "(" + lit + ")")
+	}
+
+	switch t {
+	case typeOfTime:
+		writeGoTime(b, v.Interface().(time.Time))
+		return
+	case typeOfDuration:
+		d := time.Duration(v.Int())
+		for _, u := range goDurationUnits {
+			if d != 0 && d%u.unit == 0 {
+				switch n := d / u.unit; n {
+				case 1:
+					b.WriteString(u.name)
+				case -1:
+					b.WriteString("-" + u.name)
+				default:
+					b.WriteString(strconv.FormatInt(int64(n), 10) + " * " + u.name)
+				}
+				return
+			}
+		}
+		b.WriteString("time.Duration(" + strconv.FormatInt(int64(d), 10) + ")")
+		return
+	}
+	if isGoError(v) {
+		err, _ := v.Interface().(error)
+		b.WriteString("errors.New(" + strconv.Quote(err.Error()) + ")")
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		convert(strconv.FormatBool(v.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		convert(strconv.FormatInt(v.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		convert(strconv.FormatUint(v.Uint(), 10))
+
+	case reflect.Float32, reflect.Float64:
+		convert(goFloat(v.Float(), t.Bits()))
+
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		convert("complex(" + goFloat(real(c), t.Bits()/2) + ", " + goFloat(imag(c), t.Bits()/2) + ")")
+
+	case reflect.String:
+		convert(strconv.Quote(v.String()))
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			convert("nil")
+			return
+		}
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, s.path) {
+			b.WriteString("nil /* " + p.circularRefToken() + " */")
+			return
+		}
+		defer delete(s.ptrs, ptr)
+		switch t.Elem().Kind() {
+		case reflect.Struct, reflect.Array, reflect.Slice, reflect.Map:
+			if t.Elem() == typeOfTime {
+				break
+			}
+			if !(elide && static == t) {
+				b.WriteString("&")
+			}
+			p.writeGo(b, v.Elem(), t.Elem(), elide && static == t, s.nested())
+			return
+		}
+		// Pointers to non composite values need a variable
+		// declared with the element type because the literal
+		// is written without conversion to that type
+		b.WriteString("func() " + t.String() + " { var v " + t.Elem().String() + " = ")
+		p.writeGo(b, v.Elem(), t.Elem(), false, s.nested())
+		b.WriteString("; return &v }()")
+
+	case reflect.Slice:
+		if v.IsNil() {
+			convert("nil")
+			return
+		}
+		if t.Elem().Kind() == reflect.Uint8 && utf8.Valid(v.Bytes()) {
+			b.WriteString(goTypeName(t) + "(" + strconv.Quote(string(v.Bytes())) + ")")
+			return
+		}
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, s.path) {
+			b.WriteString("nil /* " + p.circularRefToken() + " */")
+			return
+		}
+		defer delete(s.ptrs, ptr)
+		p.writeGoList(b, v, static, elide, s)
+
+	case reflect.Array:
+		p.writeGoList(b, v, static, elide, s)
+
+	case reflect.Map:
+		if v.IsNil() {
+			convert("nil")
+			return
+		}
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, s.path) {
+			b.WriteString("nil /* " + p.circularRefToken() + " */")
+			return
+		}
+		defer delete(s.ptrs, ptr)
+		if !(elide && static == t) {
+			b.WriteString(goTypeName(t))
+		}
+		b.WriteString("{")
+		keys := v.MapKeys()
+		p.sortReflectValues(keys, t.Key(), s)
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			p.writeGo(b, key, t.Key(), true, s.nested())
+			b.WriteString(": ")
+			p.writeGo(b, v.MapIndex(key), t.Elem(), true, s.nested())
+		}
+		b.WriteString("}")
+
+	case reflect.Struct:
+		if !(elide && static == t) {
+			b.WriteString(goTypeName(t))
+		}
+		b.WriteString("{")
+		written := 0
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			field := v.Field(i)
+			if f.PkgPath != "" || field.IsZero() {
+				// Unexported or zero
+				continue
+			}
+			if written > 0 {
+				b.WriteString(", ")
+			}
+			written++
+			b.WriteString(f.Name + ": ")
+			p.writeGo(b, field, f.Type, false, s.nested())
+		}
+		b.WriteString("}")
+
+	case reflect.Interface:
+		// Only reached for nil interfaces with unknown static type
+		b.WriteString("nil")
+
+	default:
+		// Func, Chan, UnsafePointer
+		if v.IsNil() {
+			convert("nil")
+			return
+		}
+		b.WriteString("nil /* " + t.String() + " */")
+	}
+}
+
+// writeGoList writes the elements of the slice or array v
+func (p *Printer) writeGoList(b *strings.Builder, v reflect.Value, static reflect.Type, elide bool, s printState) {
+	t := v.Type()
+	if !(elide && static == t) {
+		b.WriteString(goTypeName(t))
+	}
+	b.WriteString("{")
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		p.writeGo(b, v.Index(i), t.Elem(), true, s.nested())
+	}
+	b.WriteString("}")
+}
+
+// isGoError returns if v is an error without exported
+// fields that can be written as errors.New call
+func isGoError(v reflect.Value) bool {
+	if !v.Type().Implements(typeOfError) || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return false
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() != reflect.Struct || len(exportedFields(t)) == 0
+}
+
+// goTypeName returns the name of t as used in Go code,
+// wrapping pointer and function types in parentheses
+// for conversions like (*T)(nil).
+func goTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Func, reflect.Chan:
+		return "(" + t.String() + ")"
+	}
+	return t.String()
+}
+
+// goFloat returns f as Go float literal
+// that can't be mistaken for an integer constant
+func goFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "math.NaN()"
+	case math.IsInf(f, 1):
+		return "math.Inf(1)"
+	case math.IsInf(f, -1):
+		return "math.Inf(-1)"
+	}
+	str := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	return str
+}
+
+// writeGoTime writes tm as time.Date call
+func writeGoTime(b *strings.Builder, tm time.Time) {
+	var loc string
+	switch tm.Location() {
+	case time.UTC:
+		loc = "time.UTC"
+	case time.Local:
+		loc = "time.Local"
+	default:
+		name, offset := tm.Zone()
+		loc = "time.FixedZone(" + strconv.Quote(name) + ", " + strconv.Itoa(offset) + ")"
+	}
+	b.WriteString("time.Date(" +
+		strconv.Itoa(tm.Year()) + ", time." + tm.Month().String() + ", " +
+		strconv.Itoa(tm.Day()) + ", " + strconv.Itoa(tm.Hour()) + ", " +
+		strconv.Itoa(tm.Minute()) + ", " + strconv.Itoa(tm.Second()) + ", " +
+		strconv.Itoa(tm.Nanosecond()) + ", " + loc + ")")
+}
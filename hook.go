@@ -0,0 +1,55 @@
+package pretty
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+)
+
+// globalHook holds the hookFunc set by SetGlobalHook
+var globalHook atomic.Value
+
+// hookFunc wraps the hook because
+// atomic.Value can't store nil
+type hookFunc struct {
+	hook func(value any, rendered []byte)
+}
+
+// SetGlobalHook sets a function that is called with every value
+// and its rendered output printed by the Print, Println, Eprint,
+// Eprintln, Fprint and Fprintln functions and methods of all Printers,
+// for example to mirror pretty dumps into a debug ring buffer
+// without changing call sites.
+// Functions returning strings like Sprint don't call the hook.
+// The rendered output doesn't include the newline
+// appended by the Println variants and must not be modified.
+// The hook is called before the output is written
+// from the goroutine that prints the value,
+// so it must be safe for concurrent use.
+// Passing nil removes the hook.
+// Safe for concurrent use with printing.
+func SetGlobalHook(hook func(value any, rendered []byte)) {
+	globalHook.Store(hookFunc{hook})
+}
+
+// fprintIndentHooked works like fprintIndent
+// but calls the global hook if one is set
+// and highlights the output with p.Colors.
+func (p *Printer) fprintIndentHooked(w io.Writer, value any, indent []string) (endsWithNewLine bool) {
+	h, _ := globalHook.Load().(hookFunc)
+	colored := p.useColors(w)
+	if h.hook == nil && !colored {
+		return p.fprintIndent(w, value, indent)
+	}
+	var buf bytes.Buffer
+	endsWithNewLine = p.fprintIndent(&buf, value, indent)
+	if h.hook != nil {
+		h.hook(value, buf.Bytes())
+	}
+	out := buf.Bytes()
+	if colored {
+		out = p.colorize(out)
+	}
+	w.Write(out) //#nosec G104
+	return endsWithNewLine
+}
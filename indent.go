@@ -1,6 +1,8 @@
 package pretty
 
 import (
+	"bytes"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 )
@@ -9,37 +11,397 @@ import (
 // and an optional linePrefix used for every line in case of
 // a multiple line result.
 func Indent(source []byte, indent string, linePrefix ...string) []byte {
-	const (
-		stateDefault = iota
-		stateRawString
-		stateEscString
+	return indentSource(source, indentOptions{
+		levelIndent: func(int) string { return indent },
+		linePrefix:  linePrefix,
+	})
+}
+
+// IndentShrinking works like Indent but uses shrunkIndent
+// instead of indent for every nesting level deeper than depth
+// to keep deeply nested content readable.
+// A shrunkIndent like "| " can be used to draw guides.
+func IndentShrinking(source []byte, indent string, depth int, shrunkIndent string, linePrefix ...string) []byte {
+	return indentSource(source, indentOptions{
+		levelIndent: func(level int) string {
+			if level > depth {
+				return shrunkIndent
+			}
+			return indent
+		},
+		linePrefix: linePrefix,
+	})
+}
+
+// IndentBrackets works like Indent but for source
+// printed with the Printer.Brackets option.
+// Every bracket outside of strings is treated as a group,
+// so for brackets like "()" that are also used by tokens
+// like Time(…) the source should be printed indented
+// by the Printer which only groups containers.
+func IndentBrackets(source []byte, brackets string, indent string, linePrefix ...string) []byte {
+	opening, closing := parseBrackets(brackets)
+	return indentSource(source, indentOptions{
+		levelIndent: func(int) string { return indent },
+		linePrefix:  linePrefix,
+		open:        opening,
+		close:       closing,
+	})
+}
+
+// IndentDepthPrefix works like Indent but calls linePrefix
+// with the nesting depth starting at 0 of every line
+// to get the prefix of that line, for example to
+// generate nested markdown quotes or Go doc comments.
+func IndentDepthPrefix(source []byte, indent string, linePrefix func(depth int) string) []byte {
+	return indentSource(source, indentOptions{
+		levelIndent: func(int) string { return indent },
+		depthPrefix: linePrefix,
+	})
+}
+
+// parseBrackets returns the first two runes of brackets
+// or the default curly braces if brackets has less than two runes.
+func parseBrackets(brackets string) (opening, closing rune) {
+	runes := []rune(brackets)
+	if len(runes) < 2 {
+		return '{', '}'
+	}
+	return runes[0], runes[1]
+}
+
+type indentOptions struct {
+	// levelIndent returns the indent string
+	// of every nesting level starting at 1
+	levelIndent func(level int) string
+	linePrefix  []string
+	// depthPrefix returns the prefix of a line
+	// with the nesting depth starting at 0
+	// and replaces linePrefix if not nil
+	depthPrefix func(depth int) string
+	// open and close brackets, defaulting to '{' and '}'
+	open, close rune
+	// inlineMaxWidth is the maximum rune count of a group
+	// including its brackets that will be kept on one line
+	inlineMaxWidth int
+	// maxLineWidth is the maximum rune count of a line
+	// used to lay out lists of scalar elements in columns
+	maxLineWidth int
+	// trailingSeparators ends every member line
+	// of an expanded group with a semicolon
+	trailingSeparators bool
+	// fitLineWidth keeps groups on their line
+	// if the line is not wider than fitLineWidth runes
+	fitLineWidth int
+}
+
+// inlineGroupEnd returns the index after the close bracket
+// matching the open bracket at source[start] if the group
+// is not wider than maxWidth runes, else -1.
+// If spaced is true, then the spaces appended by appendInline
+// after every colon and semicolon are included in the width.
+// Returns 0 if source ends before the group is closed
+// within maxWidth runes.
+func inlineGroupEnd(source []byte, start, maxWidth int, spaced bool, opts *indentOptions) int {
+	var (
+		depth     = 0
+		width     = 0
+		inRaw     = false
+		inEscaped = false
 	)
+	for i := start; i < len(source); {
+		r, size := utf8.DecodeRune(source[i:])
+		width++
+		if spaced && !inRaw && !inEscaped && (r == ':' || r == ';') {
+			width++
+		}
+		if width > maxWidth {
+			return -1
+		}
+		switch {
+		case inRaw:
+			inRaw = r != '`'
+		case inEscaped:
+			if r == '\\' && i+1 < len(source) {
+				size++
+			} else {
+				inEscaped = r != '"'
+			}
+		case r == '`':
+			inRaw = true
+		case r == '"':
+			inEscaped = true
+		case r == '\'':
+			if end := runeLiteralEnd(source, i); end > 0 {
+				width += utf8.RuneCount(source[i+1 : end])
+				size = end - i
+			}
+		case r == opts.open:
+			depth++
+		case r == opts.close:
+			depth--
+			if depth == 0 {
+				return i + size
+			}
+		}
+		i += size
+	}
+	return 0
+}
+
+// maxRuneLiteralLen is the length of the longest
+// rune literal printed for RunesAsChars like '\U0010ffff'
+const maxRuneLiteralLen = 12
+
+// runeLiteralEnd returns the index after the quoted rune
+// like 'a' or '\n' starting at source[start] as printed
+// for Printer.RunesAsChars, so that brackets and separators
+// within it are not indented.
+// Returns -1 if there is no rune literal at start,
+// like for the apostrophe in don't that follows a letter,
+// or 0 if source ends before it can be decided.
+func runeLiteralEnd(source []byte, start int) int {
+	if prev, _ := utf8.DecodeLastRune(source[:start]); start > 0 && isIdentRune(prev) {
+		return -1
+	}
+	for end := start + 3; end <= len(source) && end-start <= maxRuneLiteralLen; end++ {
+		if source[end-1] != '\'' {
+			continue
+		}
+		if _, err := strconv.Unquote(string(source[start:end])); err == nil {
+			return end
+		}
+	}
+	if len(source)-start < maxRuneLiteralLen {
+		return 0
+	}
+	return -1
+}
+
+// appendInline appends the group to result with a space
+// after every colon and semicolon outside of strings.
+func appendInline(result, group []byte) []byte {
+	var (
+		inRaw     = false
+		inEscaped = false
+	)
+	for i := 0; i < len(group); i++ {
+		c := group[i]
+		result = append(result, c)
+		switch {
+		case inRaw:
+			inRaw = c != '`'
+		case inEscaped:
+			if c == '\\' && i+1 < len(group) {
+				i++
+				result = append(result, group[i])
+			} else {
+				inEscaped = c != '"'
+			}
+		case c == '`':
+			inRaw = true
+		case c == '"':
+			inEscaped = true
+		case c == '\'':
+			if end := runeLiteralEnd(group, i); end > 0 {
+				result = append(result, group[i+1:end]...)
+				i = end - 1
+			}
+		case c == ':' || c == ';':
+			result = append(result, ' ')
+		}
+	}
+	return result
+}
+
+// scalarListElems returns the comma separated elements
+// of the list in square brackets starting at source[start]
+// and the index after its closing bracket.
+// Returns nil and -1 if the list contains nested brackets,
+// parentheses or open group brackets, or nil and 0 if source ends before
+// the closing bracket.
+func scalarListElems(source []byte, start int, open rune) (elems [][]byte, end int) {
 	var (
-		state         = stateDefault
-		newLineIndent = "\n" + strings.Join(linePrefix, "")
-		result        = make([]byte, 0, len(source)+256)
-		unwritten     = 0
-		i             int
-		r             rune
-		rSize         int
+		openBytes = []byte(string(open))
+		inRaw     = false
+		inEscaped = false
+		elemStart = start + 1
+	)
+	for i := start + 1; i < len(source); i++ {
+		c := source[i]
+		switch {
+		case inRaw:
+			inRaw = c != '`'
+		case inEscaped:
+			if c == '\\' {
+				i++
+			} else {
+				inEscaped = c != '"'
+			}
+		case c == '`':
+			inRaw = true
+		case c == '"':
+			inEscaped = true
+		case c == '\'':
+			if end := runeLiteralEnd(source, i); end > 0 {
+				i = end - 1
+			}
+		case c == ',':
+			elems = append(elems, source[elemStart:i])
+			elemStart = i + 1
+		case c == ']':
+			if i > elemStart {
+				elems = append(elems, source[elemStart:i])
+			}
+			return elems, i + 1
+		case strings.IndexByte("[]{}()<>", c) >= 0 || bytes.HasPrefix(source[i:], openBytes):
+			return nil, -1
+		}
+	}
+	return nil, 0
+}
+
+// appendColumns appends a new line starting with prefix
+// for every row of elems laid out in aligned columns
+// so that no line is wider than maxLineWidth runes.
+// Returns nil if less than two columns fit into maxLineWidth.
+func appendColumns(result []byte, elems [][]byte, prefix string, maxLineWidth int) []byte {
+	cellWidth := 0
+	for _, elem := range elems {
+		// Every element is followed by a comma
+		if w := utf8.RuneCount(elem) + 1; w > cellWidth {
+			cellWidth = w
+		}
+	}
+	// Cells are separated by a space
+	columns := (maxLineWidth - utf8.RuneCountInString(prefix) + 1) / (cellWidth + 1)
+	if columns < 2 {
+		return nil
+	}
+	for i, elem := range elems {
+		if i%columns == 0 {
+			result = append(result, '\n')
+			result = append(result, prefix...)
+		} else {
+			result = append(result, ' ')
+		}
+		result = append(result, elem...)
+		if i == len(elems)-1 {
+			break
+		}
+		result = append(result, ',')
+		if (i+1)%columns != 0 {
+			pad := cellWidth - utf8.RuneCount(elem) - 1
+			result = append(result, strings.Repeat(" ", pad)...)
+		}
+	}
+	return result
+}
+
+// lineWidth returns the rune count of the last line of result
+func lineWidth(result []byte) int {
+	return utf8.RuneCount(result[bytes.LastIndexByte(result, '\n')+1:])
+}
+
+func indentSource(source []byte, opts indentOptions) []byte {
+	st := newIndentState(opts)
+	result, _ := st.indent(make([]byte, 0, len(source)+256), source, true)
+	return result
+}
+
+const (
+	stateDefault = iota
+	stateRawString
+	stateEscString
+)
+
+// indentState is the state of indenting source
+// that is preserved between chunks of streamed source
+type indentState struct {
+	opts        indentOptions
+	levelIndent func(level int) string
+	depthPrefix func(depth int) string
+	closeSize   int
+
+	started    bool
+	state      int
+	indents    string
+	indentLens []int
+	// lastLineWidth is the rune count of the last line
+	// of the result of previous chunks
+	lastLineWidth int
+}
+
+func newIndentState(opts indentOptions) *indentState {
+	if opts.open == 0 || opts.close == 0 {
+		opts.open, opts.close = '{', '}'
+	}
+	st := &indentState{
+		opts:        opts,
+		levelIndent: opts.levelIndent,
+		depthPrefix: opts.depthPrefix,
+		closeSize:   utf8.RuneLen(opts.close),
+	}
+	if st.depthPrefix == nil {
+		prefix := strings.Join(opts.linePrefix, "")
+		st.depthPrefix = func(int) string { return prefix }
+	}
+	return st
+}
+
+// indent appends the indented source to result and returns it
+// together with the number of consumed source bytes.
+// If final is false, then source may be followed by more source
+// and indent stops before the first token that can't be indented
+// without the following source, like an incomplete UTF-8 rune,
+// an escape sequence or a bracket group that might be inlined.
+// The returned count of consumed bytes is always len(source)
+// if final is true.
+func (st *indentState) indent(result, source []byte, final bool) ([]byte, int) {
+	var (
+		opts      = &st.opts
+		start     = len(result)
+		unwritten = 0
+		i         int
+		r         rune
+		rSize     int
 
 		appendUnwritten = func() {
 			next := i + rSize
 			result = append(result, source[unwritten:next]...)
 			unwritten = next
 		}
+		appendNewLineIndent = func() {
+			result = append(result, '\n')
+			result = append(result, st.depthPrefix(len(st.indentLens))...)
+			result = append(result, st.indents...)
+		}
+		lineWidth = func() int {
+			if bytes.IndexByte(result[start:], '\n') >= 0 {
+				return lineWidth(result)
+			}
+			return st.lastLineWidth + utf8.RuneCount(result[start:])
+		}
+		// stop returns result and the consumed bytes
+		// if source ends before the token at i
+		stop = func() ([]byte, int) {
+			result = append(result, source[unwritten:i]...)
+			st.lastLineWidth = lineWidth()
+			return result, i
+		}
 	)
 	for i = 0; i < len(source); i += rSize {
-		r, rSize = utf8.DecodeRune(source[i:])
-		if r == utf8.RuneError {
-			break
+		if !final && !utf8.FullRune(source[i:]) {
+			return stop()
 		}
-		if i == 0 {
-			for _, prefix := range linePrefix {
-				result = append(result, prefix...)
-			}
+		// Invalid UTF-8 bytes are decoded as utf8.RuneError
+		// with size 1 and copied unchanged
+		r, rSize = utf8.DecodeRune(source[i:])
+		if !st.started {
+			result = append(result, st.depthPrefix(0)...)
+			st.started = true
 		}
-		switch state {
+		switch st.state {
 		case stateDefault:
 			switch r {
 			case ':':
@@ -48,28 +410,88 @@ func Indent(source []byte, indent string, linePrefix ...string) []byte {
 			case ';':
 				result = append(result, source[unwritten:i]...)
 				unwritten = i + 1
-				result = append(result, newLineIndent...)
-			case '{':
+				if opts.trailingSeparators {
+					result = append(result, ';')
+				}
+				appendNewLineIndent()
+			case opts.open:
+				maxWidth, spaced := opts.inlineMaxWidth, false
+				if opts.fitLineWidth > 0 {
+					// Keep the group on the line if it fits
+					maxWidth, spaced = opts.fitLineWidth-lineWidth(), true
+				}
+				if maxWidth > 0 {
+					end := inlineGroupEnd(source, i, maxWidth, spaced, opts)
+					if end == 0 && !final {
+						return stop()
+					}
+					if end > 0 {
+						result = append(result, source[unwritten:i]...)
+						result = appendInline(result, source[i:end])
+						unwritten = end
+						rSize = end - i
+						continue
+					}
+				}
+				if i+rSize == len(source) && !final {
+					return stop()
+				}
 				appendUnwritten()
-				if i+1 < len(source) && source[i+1] == '}' {
+				if next, _ := utf8.DecodeRune(source[i+rSize:]); next == opts.close {
 					// no newLineIndent for {}
-					result = append(result, '}')
-					unwritten++
-					i++
+					result = utf8.AppendRune(result, opts.close)
+					unwritten += st.closeSize
+					i += st.closeSize
 					continue
 				}
-				newLineIndent += indent
-				result = append(result, newLineIndent...)
-			case '}':
+				indent := st.levelIndent(len(st.indentLens) + 1)
+				st.indentLens = append(st.indentLens, len(indent))
+				st.indents += indent
+				appendNewLineIndent()
+			case opts.close:
 				result = append(result, source[unwritten:i]...)
-				unwritten = i + 1
-				newLineIndent = newLineIndent[:len(newLineIndent)-len(indent)]
-				result = append(result, newLineIndent...)
-				result = append(result, '}')
+				unwritten = i + rSize
+				if opts.trailingSeparators && len(st.indentLens) > 0 {
+					result = append(result, ';')
+				}
+				if n := len(st.indentLens); n > 0 {
+					st.indents = st.indents[:len(st.indents)-st.indentLens[n-1]]
+					st.indentLens = st.indentLens[:n-1]
+				}
+				appendNewLineIndent()
+				result = utf8.AppendRune(result, opts.close)
+			case '[':
+				if opts.maxLineWidth > 0 {
+					elems, end := scalarListElems(source, i, opts.open)
+					if end == 0 && !final {
+						return stop()
+					}
+					result = append(result, source[unwritten:i]...)
+					unwritten = i
+					if len(elems) > 1 && lineWidth()+utf8.RuneCount(source[i:end]) > opts.maxLineWidth {
+						prefix := st.depthPrefix(len(st.indentLens)+1) + st.indents + st.levelIndent(len(st.indentLens)+1)
+						if columns := appendColumns(nil, elems, prefix, opts.maxLineWidth); columns != nil {
+							result = append(result, '[')
+							result = append(result, columns...)
+							appendNewLineIndent()
+							result = append(result, ']')
+							unwritten = end
+							rSize = end - i
+						}
+					}
+				}
 			case '`':
-				state = stateRawString
+				st.state = stateRawString
 			case '"':
-				state = stateEscString
+				st.state = stateEscString
+			case '\'':
+				end := runeLiteralEnd(source, i)
+				if end == 0 && !final {
+					return stop()
+				}
+				if end > 0 {
+					rSize = end - i
+				}
 			}
 
 		case stateRawString:
@@ -77,7 +499,7 @@ func Indent(source []byte, indent string, linePrefix ...string) []byte {
 				next := i + rSize
 				result = append(result, source[unwritten:next]...)
 				unwritten = next
-				state = stateDefault
+				st.state = stateDefault
 			}
 
 		case stateEscString:
@@ -86,23 +508,22 @@ func Indent(source []byte, indent string, linePrefix ...string) []byte {
 				next := i + rSize
 				result = append(result, source[unwritten:next]...)
 				unwritten = next
-				state = stateDefault
+				st.state = stateDefault
 
 			case '\\':
 				next := i + 1
+				if next == len(source) && !final {
+					return stop()
+				}
 				if next < len(source) && (source[next] == '\\' || source[next] == '"') {
 					// Skip next character to prevent interpreting it as string end
 					rSize = 2
 				}
-				// tail0 := string(source[i:])
-				// _, _, tail1, err := strconv.UnquoteChar(tail0, '"')
-				// if err != nil {
-				// 	continue
-				// }
-				// rSize = len(tail0) - len(tail1)
 			}
 		}
 	}
-
-	return result
+	// Append the rest after the last bracket or string
+	result = append(result, source[unwritten:]...)
+	st.lastLineWidth = lineWidth()
+	return result, len(source)
 }
@@ -0,0 +1,60 @@
+package pretty
+
+import "io"
+
+// Indenter indents pretty printed source like Indent
+// that is written to it in chunks of any size,
+// for example when piping large outputs.
+// The state of the indentation like open brackets
+// and strings is preserved across writes,
+// so chunk boundaries inside of escaped strings,
+// escape sequences or UTF-8 runes don't corrupt the output.
+// Source that can't be indented without the following chunk
+// is buffered until the next Write or Flush.
+// Flush must be called after the last Write.
+type Indenter struct {
+	w       io.Writer
+	state   *indentState
+	pending []byte
+	result  []byte
+}
+
+// NewIndenter returns an Indenter writing to w
+// using the passed indent string and an optional
+// linePrefix used for every line like Indent.
+func NewIndenter(w io.Writer, indent string, linePrefix ...string) *Indenter {
+	return &Indenter{
+		w: w,
+		state: newIndentState(indentOptions{
+			levelIndent: func(int) string { return indent },
+			linePrefix:  linePrefix,
+		}),
+	}
+}
+
+// Write indents the source in p and writes it to the
+// underlying writer except for a buffered incomplete tail.
+// Returns len(p) if the indented source could be written.
+func (in *Indenter) Write(p []byte) (n int, err error) {
+	in.pending = append(in.pending, p...)
+	var consumed int
+	in.result, consumed = in.state.indent(in.result[:0], in.pending, false)
+	in.pending = in.pending[:copy(in.pending, in.pending[consumed:])]
+	if _, err = in.w.Write(in.result); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush indents and writes the buffered source.
+// The Indenter can be used for more source after Flush
+// which continues the indentation state.
+func (in *Indenter) Flush() error {
+	var err error
+	in.result, _ = in.state.indent(in.result[:0], in.pending, true)
+	in.pending = in.pending[:0]
+	if len(in.result) > 0 {
+		_, err = in.w.Write(in.result)
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package pretty
+
+import (
+	"fmt"
+	"io"
+)
+
+// stringInterner keeps track of printed strings
+// to replace repeated occurrences with references.
+type stringInterner struct {
+	ids      map[string]int
+	repeated []string
+}
+
+// ref returns the reference token for the quoted string q
+// if it was already printed before, else an empty string.
+func (in *stringInterner) ref(q string) string {
+	id, seen := in.ids[q]
+	if !seen {
+		in.ids[q] = 0
+		return ""
+	}
+	if id == 0 {
+		in.repeated = append(in.repeated, q)
+		id = len(in.repeated)
+		in.ids[q] = id
+	}
+	return fmt.Sprintf("@str%d", id)
+}
+
+// fprintLegend writes the full strings of all references
+// in the order of their first repetition.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (in *stringInterner) fprintLegend(w io.Writer) {
+	for i, q := range in.repeated {
+		fmt.Fprintf(w, " @str%d=%s", i+1, q)
+	}
+}
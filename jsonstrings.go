@@ -0,0 +1,127 @@
+package pretty
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// maxEmbeddedJSONCandidates is the maximum number of opening
+// brackets that parseEmbeddedJSON tries as start of the JSON
+// to bound the work for long strings with many brackets.
+const maxEmbeddedJSONCandidates = 8
+
+// parseEmbeddedJSON returns the JSON object or array
+// at the end of str decoded as value
+// together with the text before the JSON as prefix.
+// Only the first maxEmbeddedJSONCandidates opening brackets
+// matching the closing bracket at the end of str
+// are tried as start of the JSON.
+func parseEmbeddedJSON(str string) (prefix string, value any, ok bool) {
+	trimmed := strings.TrimSpace(str)
+	if trimmed == "" {
+		return "", nil, false
+	}
+	var opening byte
+	switch trimmed[len(trimmed)-1] {
+	case '}':
+		opening = '{'
+	case ']':
+		opening = '['
+	default:
+		return "", nil, false
+	}
+	for i, candidates := 0, 0; i < len(trimmed) && candidates < maxEmbeddedJSONCandidates; i++ {
+		if trimmed[i] != opening {
+			continue
+		}
+		candidates++
+		candidate := trimmed[i:]
+		if !json.Valid([]byte(candidate)) {
+			continue
+		}
+		if json.Unmarshal([]byte(candidate), &value) != nil {
+			return "", nil, false
+		}
+		return strings.TrimSpace(trimmed[:i]), value, true
+	}
+	return "", nil, false
+}
+
+// fprintJSONString prints str as decoded JSON
+// prefixed with JSON if p.DetectJSONStrings is true
+// and str is a JSON object or array.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintJSONString(w io.Writer, str string, s printState) bool {
+	if !p.DetectJSONStrings {
+		return false
+	}
+	prefix, value, ok := parseEmbeddedJSON(str)
+	if !ok || prefix != "" {
+		return false
+	}
+	io.WriteString(w, "JSON")
+	p.fprint(w, reflect.ValueOf(value), s)
+	return true
+}
+
+// fprintError prints err as error(`message`).
+// If p.DetectJSONStrings is true and the message ends with
+// a JSON object or array like typical HTTP client errors,
+// then it is printed as error{Message:`prefix`;JSON:{…}}
+// so that the JSON is expanded beneath the message in indented output.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintError(w io.Writer, err error, s printState) {
+	if p.DetectJSONStrings {
+		if prefix, value, ok := parseEmbeddedJSON(err.Error()); ok {
+			opening, closing := p.brackets()
+			fmt.Fprintf(w, "error%sMessage:%s;JSON:", opening, p.quote(prefix, p.MaxErrorLength, s))
+			p.fprint(w, reflect.ValueOf(value), s.field("JSON"))
+			io.WriteString(w, closing)
+			return
+		}
+	}
+	fmt.Fprintf(w, "error(%s)", p.quote(err, p.MaxErrorLength, s))
+}
+
+// fprintJSONMarshaler prints the JSON of v if p.UseJSONMarshaler
+// is true and v or its address implements json.Marshaler.
+// Objects and arrays are printed prefixed with JSON like with
+// DetectJSONStrings and other JSON values as their decoded value.
+// Returns false if v was not printed because it's not
+// a json.Marshaler or marshalling failed.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintJSONMarshaler(w io.Writer, v reflect.Value, s printState) bool {
+	if !p.UseJSONMarshaler {
+		return false
+	}
+	marshaler, _ := v.Interface().(json.Marshaler)
+	if marshaler == nil && v.CanAddr() {
+		marshaler, _ = v.Addr().Interface().(json.Marshaler)
+	}
+	if marshaler == nil {
+		return false
+	}
+	data, err := marshaler.MarshalJSON()
+	if err != nil {
+		return false
+	}
+	var value any
+	if json.Unmarshal(data, &value) != nil {
+		return false
+	}
+	switch value.(type) {
+	case nil:
+		io.WriteString(w, p.nullToken())
+		return true
+	case map[string]any, []any:
+		io.WriteString(w, "JSON")
+	}
+	p.fprint(w, reflect.ValueOf(value), s)
+	return true
+}
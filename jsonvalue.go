@@ -0,0 +1,152 @@
+package pretty
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	typeOfJSONObject = reflect.TypeOf(map[string]any(nil))
+	typeOfJSONArray  = reflect.TypeOf([]any(nil))
+)
+
+// fprintJSONValue prints a map[string]any or []any
+// and all values nested in it in JSON syntax
+// with sorted keys if p.JSONValueMode is true.
+// If the output will be indented, then object members
+// are separated by semicolons instead of commas
+// so that every member is printed on its own line.
+// The configured limits are applied with an ellipsis
+// marking truncated strings, arrays and objects.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintJSONValue(w io.Writer, v reflect.Value, s printState) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		io.WriteString(w, "null")
+		return
+	}
+	switch v.Type() {
+	case typeOfJSONObject, typeOfJSONArray:
+		if v.IsNil() {
+			io.WriteString(w, "null")
+			return
+		}
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, s.path) {
+			io.WriteString(w, p.circularRef(s.ptrs[ptr]))
+			return
+		}
+		defer delete(s.ptrs, ptr)
+	}
+
+	switch x := v.Interface().(type) {
+	case nil:
+		io.WriteString(w, "null")
+
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for key := range x {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		n := len(keys)
+		if maxLen := s.limit(p.MaxMapLength); maxLen > 0 && n > maxLen {
+			s.truncated(n, maxLen)
+			n = maxLen
+		}
+		sep := ","
+		if s.indented {
+			sep = ";"
+		}
+		opening, closing := p.brackets()
+		io.WriteString(w, opening)
+		for i, key := range keys[:n] {
+			if i > 0 {
+				io.WriteString(w, sep)
+			}
+			io.WriteString(w, p.jsonString(key, 0, s))
+			io.WriteString(w, ":")
+			p.fprintJSONValue(w, reflect.ValueOf(x[key]), s.key(reflect.ValueOf(key)))
+		}
+		if n < len(keys) {
+			io.WriteString(w, sep+p.ellipsis()+"+"+strconv.Itoa(len(keys)-n)+" more")
+		}
+		io.WriteString(w, closing)
+
+	case []any:
+		n := len(x)
+		if maxLen := s.limit(p.MaxSliceLength); maxLen > 0 && n > maxLen {
+			s.truncated(n, maxLen)
+			n = maxLen
+		}
+		io.WriteString(w, "[")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			p.fprintJSONValue(w, reflect.ValueOf(x[i]), s.index(i))
+		}
+		if n < len(x) {
+			io.WriteString(w, ","+p.ellipsis())
+		}
+		io.WriteString(w, "]")
+
+	case string:
+		io.WriteString(w, p.jsonString(x, s.limit(p.MaxStringLength), s))
+
+	case bool:
+		io.WriteString(w, strconv.FormatBool(x))
+
+	case float64:
+		io.WriteString(w, formatJSONNumber(x))
+
+	case json.Number:
+		io.WriteString(w, x.String())
+
+	default:
+		p.fprint(w, v, s)
+	}
+}
+
+// jsonString returns str as JSON string in double quotes
+// truncated to maxLen bytes with an ellipsis if maxLen > 0.
+func (p *Printer) jsonString(str string, maxLen int, s printState) string {
+	if p.SanitizeForLogs {
+		str = sanitizeString(str)
+	}
+	str = p.redact(str)
+	if maxLen > 0 && len(str) > maxLen {
+		s.truncated(len(str), maxLen)
+		n := maxLen
+		for n > 0 && !utf8.RuneStart(str[n]) {
+			n--
+		}
+		str = str[:n] + p.ellipsis()
+	}
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(str) //#nosec G104 -- can't fail for strings
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatJSONNumber formats f like encoding/json
+// without exponent for common magnitudes.
+func formatJSONNumber(f float64) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(f, 'e', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
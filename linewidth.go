@@ -0,0 +1,49 @@
+package pretty
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"unicode/utf8"
+)
+
+// fprintFitLineWidth prints value for the FitLineWidth option
+// on a single line if it fits into the line width of w,
+// else indented with groups kept on their line if they fit.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintFitLineWidth(w io.Writer, value any) (endsWithNewLine bool) {
+	width := p.lineWidth(w)
+	var buf bytes.Buffer
+	g := p.groupPrinter()
+	g.fprintIndentLimited(&buf, value, nil)
+	if utf8.RuneCount(buf.Bytes()) <= width {
+		w.Write(p.replaceGroupDelimiters(buf.Bytes()))
+		return false
+	}
+	opts := g.indentOptions("  ", nil)
+	opts.fitLineWidth = width
+	if opts.maxLineWidth <= 0 {
+		opts.maxLineWidth = width
+	}
+	out := p.replaceGroupDelimiters(indentSource(buf.Bytes(), opts))
+	w.Write(out)
+	return len(out) > 0 && out[len(out)-1] == '\n'
+}
+
+// lineWidth returns the line width in runes for output written to w
+func (p *Printer) lineWidth(w io.Writer) int {
+	if p.MaxLineWidth > 0 {
+		return p.MaxLineWidth
+	}
+	if f, ok := w.(*os.File); ok {
+		if width := terminalWidth(f); width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return DefaultLineWidth
+}
@@ -0,0 +1,70 @@
+package pretty
+
+import "strings"
+
+// MetaRunes defines the strings printed instead of the
+// non-ASCII meta characters that the printer emits itself,
+// for example for log ingestion systems that can't handle them.
+// Empty strings keep the default meta characters.
+// Non-ASCII characters of printed values are not substituted,
+// see Printer.SanitizeForLogs and Printer.InvalidUTF8 for those.
+type MetaRunes struct {
+	// Ellipsis replaces … that marks truncated values
+	Ellipsis string
+	// Arrow replaces → between the old and new values of diffs
+	Arrow string
+	// Replacement replaces the Unicode replacement character
+	// used for invalid UTF-8 with InvalidUTF8Replace
+	Replacement string
+	// Times replaces × in the run length of whitespace
+	// collapsed with CollapseWhitespace like (×12)
+	Times string
+}
+
+// ASCIIMetaRunes substitutes all non-ASCII meta characters
+// with ASCII strings when used for Printer.MetaRunes.
+var ASCIIMetaRunes = MetaRunes{
+	Ellipsis:    "...",
+	Arrow:       "->",
+	Replacement: "?",
+	Times:       "x",
+}
+
+// ellipsis returns the string marking truncated values
+func (p *Printer) ellipsis() string {
+	if p.MetaRunes == nil || p.MetaRunes.Ellipsis == "" {
+		return "…"
+	}
+	return p.MetaRunes.Ellipsis
+}
+
+// arrow returns the string between old and new values of diffs
+func (p *Printer) arrow() string {
+	if p.MetaRunes == nil || p.MetaRunes.Arrow == "" {
+		return "→"
+	}
+	return p.MetaRunes.Arrow
+}
+
+// replacementChar returns the string replacing invalid UTF-8
+func (p *Printer) replacementChar() string {
+	if p.MetaRunes == nil || p.MetaRunes.Replacement == "" {
+		return "�"
+	}
+	return p.MetaRunes.Replacement
+}
+
+// times returns the string before the run length
+// of collapsed whitespace
+func (p *Printer) times() string {
+	if p.MetaRunes == nil || p.MetaRunes.Times == "" {
+		return "×"
+	}
+	return p.MetaRunes.Times
+}
+
+// totalLengthMarker returns TotalLengthMarker
+// with the ellipsis of p
+func (p *Printer) totalLengthMarker() string {
+	return strings.Replace(TotalLengthMarker, "…", p.ellipsis(), 1)
+}
@@ -0,0 +1,81 @@
+package pretty
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SprintMethods returns the type of value followed by its
+// method set in curly braces for diagnosing why a value
+// does not implement an interface at runtime, for example:
+//
+//	pretty.Item{String() string;Validate(context.Context) error(pointer receiver)}
+//
+// The methods are sorted by name and written with their signatures.
+// For non pointer types the methods with a pointer receiver
+// are listed too, marked with "(pointer receiver)",
+// because they are missing from the method set of the value.
+// Unexported methods are not listed
+// because they are not accessible via reflection.
+func SprintMethods(value any) string {
+	if value == nil {
+		return "nil"
+	}
+	t := reflect.TypeOf(value)
+	methods := t
+	if t.Kind() != reflect.Ptr {
+		// The method set of the pointer type
+		// includes the methods of the value type
+		methods = reflect.PtrTo(t)
+	}
+	var b strings.Builder
+	b.WriteString(t.String())
+	b.WriteByte('{')
+	for i := 0; i < methods.NumMethod(); i++ {
+		m := methods.Method(i)
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(m.Name + methodSignature(m.Type))
+		if _, ok := t.MethodByName(m.Name); !ok {
+			b.WriteString("(pointer receiver)")
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// methodSignature returns the parameters and results
+// of the function type f like "(int, ...string) (bool, error)"
+// skipping the first parameter which is the receiver.
+func methodSignature(f reflect.Type) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	const first = 1
+	for i := first; i < f.NumIn(); i++ {
+		if i > first {
+			b.WriteString(", ")
+		}
+		if f.IsVariadic() && i == f.NumIn()-1 {
+			b.WriteString("..." + f.In(i).Elem().String())
+		} else {
+			b.WriteString(f.In(i).String())
+		}
+	}
+	b.WriteByte(')')
+	switch f.NumOut() {
+	case 0:
+	case 1:
+		b.WriteString(" " + f.Out(0).String())
+	default:
+		b.WriteString(" (")
+		for i := 0; i < f.NumOut(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(f.Out(i).String())
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package pretty
+
+import (
+	"io"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+)
+
+var (
+	typeOfIP       = reflect.TypeOf(net.IP(nil))
+	typeOfAddr     = reflect.TypeOf(netip.Addr{})
+	typeOfAddrPort = reflect.TypeOf(netip.AddrPort{})
+	typeOfPrefix   = reflect.TypeOf(netip.Prefix{})
+	typeOfURL      = reflect.TypeOf(url.URL{})
+)
+
+// isNetType returns if t is net.IP, netip.Addr,
+// netip.AddrPort, netip.Prefix or url.URL
+func isNetType(t reflect.Type) bool {
+	switch t {
+	case typeOfIP, typeOfAddr, typeOfAddrPort, typeOfPrefix, typeOfURL:
+		return true
+	}
+	return false
+}
+
+// fprintNetValue prints an IP address, prefix or URL
+// as its quoted canonical string form like IP(`192.168.0.1`)
+// instead of bytes or nested structs.
+// Passwords of URLs are redacted.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintNetValue(w io.Writer, v reflect.Value, s printState) {
+	var name, str string
+	switch x := v.Interface().(type) {
+	case net.IP:
+		if x == nil {
+			io.WriteString(w, p.nilToken())
+			return
+		}
+		name, str = "IP", x.String()
+	case netip.Addr:
+		name, str = "Addr", x.String()
+	case netip.AddrPort:
+		name, str = "AddrPort", x.String()
+	case netip.Prefix:
+		name, str = "Prefix", x.String()
+	case url.URL:
+		name, str = "URL", x.Redacted()
+	}
+	io.WriteString(w, name+"("+p.quote(str, s.limit(p.MaxStringLength), s)+")")
+}
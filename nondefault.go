@@ -0,0 +1,70 @@
+package pretty
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// SprintNonDefault pretty prints only the exported struct fields
+// of value that differ from the same fields of prototype,
+// for example to dump the overridden fields of an effective
+// configuration compared to the default configuration.
+// Nested structs are compared recursively.
+// If value and prototype are not structs of the same type,
+// then value is printed completely.
+func (p *Printer) SprintNonDefault(value, prototype any) string {
+	var b strings.Builder
+	if value == nil {
+		b.WriteString(p.nilToken())
+		return b.String()
+	}
+	p.fprintNonDefault(&b, reflect.ValueOf(value), reflect.ValueOf(prototype), p.newPrintState())
+	return b.String()
+}
+
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintNonDefault(w io.Writer, v, proto reflect.Value, s printState) {
+	orig := v
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	for proto.Kind() == reflect.Ptr && !proto.IsNil() {
+		proto = proto.Elem()
+	}
+	if v.Kind() != reflect.Struct || !proto.IsValid() || v.Type() != proto.Type() || p.isLeafValue(v) {
+		p.fprint(w, orig, s)
+		return
+	}
+	for ptr := orig; ptr.Kind() == reflect.Ptr; ptr = ptr.Elem() {
+		if s.ptrs.visit(ptr.Pointer(), s.path) {
+			io.WriteString(w, p.circularRef(s.ptrs[ptr.Pointer()]))
+			return
+		}
+		defer delete(s.ptrs, ptr.Pointer())
+	}
+
+	opening, closing := p.brackets()
+	io.WriteString(w, v.Type().Name())
+	io.WriteString(w, opening)
+	first := true
+	for _, f := range exportedFields(v.Type()) {
+		field, protoField := v.Field(f.index), proto.Field(f.index)
+		if reflect.DeepEqual(field.Interface(), protoField.Interface()) {
+			continue
+		}
+		if first {
+			first = false
+		} else {
+			io.WriteString(w, ";")
+		}
+		fs := s.nested()
+		if !f.anonymous {
+			fmt.Fprintf(w, "%s:", f.name)
+			fs = s.field(f.name)
+		}
+		p.fprintNonDefault(w, field, protoField, fs)
+	}
+	io.WriteString(w, closing)
+}
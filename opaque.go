@@ -0,0 +1,43 @@
+package pretty
+
+import (
+	"reflect"
+	"strings"
+)
+
+// opaqueTypes are the names of runtime and synchronization types
+// by package path whose internals are meaningless and racy to read.
+// Generic types like weak.Pointer[T] are matched without type arguments.
+// Names are used instead of reflect.Type values
+// so that types of newer Go versions can be listed.
+var opaqueTypes = map[string]map[string]bool{
+	"sync": {
+		"Mutex":     true,
+		"RWMutex":   true,
+		"WaitGroup": true,
+		"Once":      true,
+		"Pool":      true,
+		"Cond":      true,
+		"Map":       true,
+	},
+	"runtime": {
+		"Pinner": true,
+	},
+	"weak": {
+		"Pointer": true,
+	},
+	"unique": {
+		"Handle": true,
+	},
+}
+
+// isOpaqueType returns if t is a runtime or synchronization type
+// that is printed only as its type name like sync.Mutex.
+func isOpaqueType(t reflect.Type) bool {
+	names := opaqueTypes[t.PkgPath()]
+	if names == nil {
+		return false
+	}
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return names[name]
+}
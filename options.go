@@ -0,0 +1,372 @@
+package pretty
+
+import (
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// Option configures a Printer created with NewPrinter
+// or derived with Printer.With.
+type Option func(*Printer)
+
+// NewPrinter returns a Printer with the default limits
+// of the default Printer configured by the passed options.
+func NewPrinter(opts ...Option) *Printer {
+	p := &Printer{
+		MaxStringLength: DefaultMaxStringLength,
+		MaxErrorLength:  DefaultMaxErrorLength,
+		MaxSliceLength:  DefaultMaxSliceLength,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// With returns a copy of the Printer configured
+// by the passed options without modifying p.
+func (p *Printer) With(opts ...Option) *Printer {
+	c := *p
+	if p.bitmasks != nil {
+		c.bitmasks = make(map[reflect.Type][]bitName, len(p.bitmasks))
+		for t, bits := range p.bitmasks {
+			c.bitmasks[t] = bits
+		}
+	}
+	if p.formatters != nil {
+		c.formatters = make(map[reflect.Type]func(any) string, len(p.formatters))
+		for t, format := range p.formatters {
+			c.formatters[t] = format
+		}
+	}
+	if p.immutables != nil {
+		c.immutables = make(map[reflect.Type]bool, len(p.immutables))
+		for t := range p.immutables {
+			c.immutables[t] = true
+		}
+		// The rendered strings depend on the configuration
+		c.renderCache = new(renderCache)
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// WithMaxStringLength sets Printer.MaxStringLength
+func WithMaxStringLength(n int) Option {
+	return func(p *Printer) { p.MaxStringLength = n }
+}
+
+// WithMaxErrorLength sets Printer.MaxErrorLength
+func WithMaxErrorLength(n int) Option {
+	return func(p *Printer) { p.MaxErrorLength = n }
+}
+
+// WithMaxSliceLength sets Printer.MaxSliceLength
+func WithMaxSliceLength(n int) Option {
+	return func(p *Printer) { p.MaxSliceLength = n }
+}
+
+// WithMaxMapLength sets Printer.MaxMapLength
+func WithMaxMapLength(n int) Option {
+	return func(p *Printer) { p.MaxMapLength = n }
+}
+
+// WithMaxStructFields sets Printer.MaxStructFields
+func WithMaxStructFields(n int) Option {
+	return func(p *Printer) { p.MaxStructFields = n }
+}
+
+// WithAppendStructErrors sets Printer.AppendStructErrors
+func WithAppendStructErrors(appendErrors bool) Option {
+	return func(p *Printer) { p.AppendStructErrors = appendErrors }
+}
+
+// WithHexByteArrays sets Printer.HexByteArrays
+func WithHexByteArrays(hex bool) Option {
+	return func(p *Printer) { p.HexByteArrays = hex }
+}
+
+// WithParallel sets Printer.Parallel
+func WithParallel(parallel bool) Option {
+	return func(p *Printer) { p.Parallel = parallel }
+}
+
+// WithInternStringsMinLength sets Printer.InternStringsMinLength
+func WithInternStringsMinLength(n int) Option {
+	return func(p *Printer) { p.InternStringsMinLength = n }
+}
+
+// WithNoLimits disables truncating of strings, errors, slices, maps and structs
+func WithNoLimits() Option {
+	return func(p *Printer) {
+		p.MaxStringLength = 0
+		p.MaxErrorLength = 0
+		p.MaxSliceLength = 0
+		p.MaxMapLength = 0
+		p.MaxStructFields = 0
+	}
+}
+
+// WithMaxDepth sets Printer.MaxDepth
+func WithMaxDepth(n int) Option {
+	return func(p *Printer) { p.MaxDepth = n }
+}
+
+// WithRunesAsChars sets Printer.RunesAsChars
+func WithRunesAsChars(asChars bool) Option {
+	return func(p *Printer) { p.RunesAsChars = asChars }
+}
+
+// WithMaxTotalLength sets Printer.MaxTotalLength
+func WithMaxTotalLength(n int) Option {
+	return func(p *Printer) { p.MaxTotalLength = n }
+}
+
+// WithOmitZero sets Printer.OmitZero
+func WithOmitZero(omit bool) Option {
+	return func(p *Printer) { p.OmitZero = omit }
+}
+
+// WithIncludeUnexported sets Printer.IncludeUnexported
+func WithIncludeUnexported(include bool) Option {
+	return func(p *Printer) { p.IncludeUnexported = include }
+}
+
+// WithSortSlices sets Printer.SortSlices to true
+// and Printer.SliceLess to less which can be nil
+// to compare the elements by their pretty representation.
+func WithSortSlices(less func(a, b any) bool) Option {
+	return func(p *Printer) {
+		p.SortSlices = true
+		p.SliceLess = less
+	}
+}
+
+// WithTimeLayout sets Printer.TimeLayout
+func WithTimeLayout(layout string) Option {
+	return func(p *Printer) { p.TimeLayout = layout }
+}
+
+// WithTimeLocation sets Printer.TimeLocation
+func WithTimeLocation(loc *time.Location) Option {
+	return func(p *Printer) { p.TimeLocation = loc }
+}
+
+// WithStripMonotonic sets Printer.StripMonotonic
+func WithStripMonotonic(strip bool) Option {
+	return func(p *Printer) { p.StripMonotonic = strip }
+}
+
+// WithDetectUnixTimestamps sets Printer.DetectUnixTimestamps
+func WithDetectUnixTimestamps(detect bool) Option {
+	return func(p *Printer) { p.DetectUnixTimestamps = detect }
+}
+
+// WithNow sets Printer.Now
+func WithNow(now func() time.Time) Option {
+	return func(p *Printer) { p.Now = now }
+}
+
+// WithDurationFormat sets Printer.DurationFormat
+func WithDurationFormat(format DurationFormatMode) Option {
+	return func(p *Printer) { p.DurationFormat = format }
+}
+
+// WithFloatFormat sets Printer.FloatFormat
+func WithFloatFormat(format FloatFormat) Option {
+	return func(p *Printer) { p.FloatFormat = format }
+}
+
+// WithColors sets Printer.Colors
+func WithColors(scheme *ColorScheme) Option {
+	return func(p *Printer) { p.Colors = scheme }
+}
+
+// WithForceColor sets Printer.ForceColor
+func WithForceColor(force bool) Option {
+	return func(p *Printer) { p.ForceColor = force }
+}
+
+// WithDisableColor sets Printer.DisableColor
+func WithDisableColor(disable bool) Option {
+	return func(p *Printer) { p.DisableColor = disable }
+}
+
+// WithFloatSpecials sets Printer.FloatSpecials
+func WithFloatSpecials(enable bool) Option {
+	return func(p *Printer) { p.FloatSpecials = enable }
+}
+
+// WithUseStringer sets Printer.UseStringer
+func WithUseStringer(use bool) Option {
+	return func(p *Printer) { p.UseStringer = use }
+}
+
+// WithJSONValueMode sets Printer.JSONValueMode
+func WithJSONValueMode(jsonMode bool) Option {
+	return func(p *Printer) { p.JSONValueMode = jsonMode }
+}
+
+// WithUseJSONMarshaler sets Printer.UseJSONMarshaler
+func WithUseJSONMarshaler(use bool) Option {
+	return func(p *Printer) { p.UseJSONMarshaler = use }
+}
+
+// WithCollapseWhitespace sets Printer.CollapseWhitespace
+func WithCollapseWhitespace(collapse bool) Option {
+	return func(p *Printer) { p.CollapseWhitespace = collapse }
+}
+
+// WithMaxLineWidth sets Printer.MaxLineWidth
+func WithMaxLineWidth(n int) Option {
+	return func(p *Printer) { p.MaxLineWidth = n }
+}
+
+// WithInlineMaxWidth sets Printer.InlineMaxWidth
+func WithInlineMaxWidth(n int) Option {
+	return func(p *Printer) { p.InlineMaxWidth = n }
+}
+
+// WithFitLineWidth sets Printer.FitLineWidth
+func WithFitLineWidth(fit bool) Option {
+	return func(p *Printer) { p.FitLineWidth = fit }
+}
+
+// WithTrailingSeparators sets Printer.TrailingSeparators
+func WithTrailingSeparators(trailing bool) Option {
+	return func(p *Printer) { p.TrailingSeparators = trailing }
+}
+
+// WithShrinkIndent sets Printer.ShrinkIndentDepth to depth
+// and Printer.ShrinkIndent to indent.
+func WithShrinkIndent(depth int, indent string) Option {
+	return func(p *Printer) {
+		p.ShrinkIndentDepth = depth
+		p.ShrinkIndent = indent
+	}
+}
+
+// WithRedactPatterns sets Printer.RedactPatterns
+func WithRedactPatterns(patterns ...*regexp.Regexp) Option {
+	return func(p *Printer) { p.RedactPatterns = patterns }
+}
+
+// WithOnTruncatedValue sets Printer.OnTruncatedValue
+func WithOnTruncatedValue(fn func(path string, v reflect.Value)) Option {
+	return func(p *Printer) { p.OnTruncatedValue = fn }
+}
+
+// WithTruncationSidecar sets Printer.TruncationSidecar
+func WithTruncationSidecar(fn func(sidecar []byte)) Option {
+	return func(p *Printer) { p.TruncationSidecar = fn }
+}
+
+// WithKeysOnly sets Printer.KeysOnly to true
+// and Printer.KeysOnlyDepth to depth.
+func WithKeysOnly(depth int) Option {
+	return func(p *Printer) {
+		p.KeysOnly = true
+		p.KeysOnlyDepth = depth
+	}
+}
+
+// WithNilToken sets Printer.NilToken
+func WithNilToken(token string) Option {
+	return func(p *Printer) { p.NilToken = token }
+}
+
+// WithNullToken sets Printer.NullToken
+func WithNullToken(token string) Option {
+	return func(p *Printer) { p.NullToken = token }
+}
+
+// WithEmptyStringToken sets Printer.EmptyStringToken
+func WithEmptyStringToken(token string) Option {
+	return func(p *Printer) { p.EmptyStringToken = token }
+}
+
+// WithCircularRefToken sets Printer.CircularRefToken
+func WithCircularRefToken(token string) Option {
+	return func(p *Printer) { p.CircularRefToken = token }
+}
+
+// WithCircularRefPath sets Printer.CircularRefPath
+func WithCircularRefPath(withPath bool) Option {
+	return func(p *Printer) { p.CircularRefPath = withPath }
+}
+
+// WithBrackets sets Printer.Brackets
+func WithBrackets(brackets string) Option {
+	return func(p *Printer) { p.Brackets = brackets }
+}
+
+// WithSanitizeForLogs sets Printer.SanitizeForLogs
+func WithSanitizeForLogs(sanitize bool) Option {
+	return func(p *Printer) { p.SanitizeForLogs = sanitize }
+}
+
+// WithInvalidUTF8 sets Printer.InvalidUTF8
+func WithInvalidUTF8(mode InvalidUTF8Mode) Option {
+	return func(p *Printer) { p.InvalidUTF8 = mode }
+}
+
+// WithMetaRunes sets Printer.MetaRunes
+func WithMetaRunes(metaRunes *MetaRunes) Option {
+	return func(p *Printer) { p.MetaRunes = metaRunes }
+}
+
+// WithStrictSingleLine sets Printer.StrictSingleLine
+func WithStrictSingleLine(strict bool) Option {
+	return func(p *Printer) { p.StrictSingleLine = strict }
+}
+
+// WithDetectUUIDs sets Printer.DetectUUIDs
+func WithDetectUUIDs(detect bool) Option {
+	return func(p *Printer) { p.DetectUUIDs = detect }
+}
+
+// WithDetectJSONStrings sets Printer.DetectJSONStrings
+func WithDetectJSONStrings(detect bool) Option {
+	return func(p *Printer) { p.DetectJSONStrings = detect }
+}
+
+// WithShowIndexes sets Printer.ShowIndexes
+func WithShowIndexes(show bool) Option {
+	return func(p *Printer) { p.ShowIndexes = show }
+}
+
+// WithInterfaceFieldsAsTypes sets Printer.InterfaceFieldsAsTypes
+func WithInterfaceFieldsAsTypes(asTypes bool) Option {
+	return func(p *Printer) { p.InterfaceFieldsAsTypes = asTypes }
+}
+
+// WithShowNamedTypeUnits sets Printer.ShowNamedTypeUnits
+func WithShowNamedTypeUnits(show bool) Option {
+	return func(p *Printer) { p.ShowNamedTypeUnits = show }
+}
+
+// WithRenderCacheSize sets Printer.RenderCacheSize
+func WithRenderCacheSize(size int) Option {
+	return func(p *Printer) { p.RenderCacheSize = size }
+}
+
+// WithImmutable registers types whose rendered
+// strings are cached, see Printer.RegisterImmutable.
+func WithImmutable(types ...reflect.Type) Option {
+	return func(p *Printer) { p.RegisterImmutable(types...) }
+}
+
+// WithBitmask registers names for the bits
+// of the integer type t, see Printer.RegisterBitmask.
+func WithBitmask(t reflect.Type, names map[uint64]string) Option {
+	return func(p *Printer) { p.RegisterBitmask(t, names) }
+}
+
+// WithFormatter registers a function that returns the printed
+// representation of values of type t, see Printer.RegisterFormatter.
+func WithFormatter(t reflect.Type, format func(v any) string) Option {
+	return func(p *Printer) { p.RegisterFormatter(t, format) }
+}
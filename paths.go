@@ -0,0 +1,238 @@
+package pretty
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	typeOfPrintable    = reflect.TypeOf((*Printable)(nil)).Elem()
+	typeOfPrinterAware = reflect.TypeOf((*PrinterAware)(nil)).Elem()
+	typeOfNullable     = reflect.TypeOf((*Nullable)(nil)).Elem()
+	typeOfContext      = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// SprintWithPaths pretty prints all leaf values of value
+// and returns them mapped by their path.
+// Struct fields and string map keys are joined with a dot,
+// slice and array indices are appended in brackets,
+// for example "Sub.Map.key" or "Items[0].Name".
+// Map keys that are empty or contain a dot, bracket
+// or double quote are quoted like "Map.\"a.b\"".
+// Slices are truncated to MaxSliceLength and maps to MaxMapLength.
+func (p *Printer) SprintWithPaths(value any) map[string]string {
+	paths := make(map[string]string)
+	p.walk(reflect.ValueOf(value), "", printState{ptrs: make(visitedPtrs)}, func(path string, v reflect.Value, s printState, leaf bool) bool {
+		if !leaf {
+			return true
+		}
+		if !v.IsValid() {
+			paths[path] = p.nilToken()
+			return false
+		}
+		var b strings.Builder
+		p.fprint(&b, v, s)
+		paths[path] = b.String()
+		return false
+	})
+	return paths
+}
+
+// Walk calls fn for value and all values nested in it
+// using the same traversal as the printing of value
+// with the same paths as SprintWithPaths.
+// Exported struct fields up to MaxStructFields, map elements up to MaxMapLength,
+// and slice and array elements up to MaxSliceLength are walked.
+// Values that are printed as a whole, like implementations
+// of Printable, PrinterAware, time.Time, strings or circular references,
+// are passed to fn but not walked further.
+// If fn returns false for a struct, map, slice or array,
+// then its elements are not walked.
+// The reflect.Value passed to fn may be a pointer
+// or interface or invalid for nil.
+func (p *Printer) Walk(value any, fn func(path string, v reflect.Value) bool) {
+	p.walk(reflect.ValueOf(value), "", printState{ptrs: make(visitedPtrs)}, func(path string, v reflect.Value, _ printState, _ bool) bool {
+		return fn(path, v)
+	})
+}
+
+// walk calls visit for v and every value nested in v.
+// The argument leaf is true for values that are not expanded
+// into further paths and the result of visit is ignored for them.
+// Else visit returns if the elements of v should be walked.
+func (p *Printer) walk(v reflect.Value, path string, s printState, visit func(path string, v reflect.Value, s printState, leaf bool) bool) {
+	if p.isLeafValue(v) {
+		visit(path, v, s, true)
+		return
+	}
+	orig := v
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		elem := v.Elem()
+		if p.isLeafValue(elem) {
+			// Visit before the pointer is marked as visited
+			// so that printing orig doesn't detect a circular reference
+			visit(path, orig, s, true)
+			return
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, path) {
+				// Let the leaf printing handle the circular reference
+				visit(path, v, s, true)
+				return
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		v = elem
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if !visit(path, orig, s, false) {
+			return
+		}
+		fields := exportedFields(v.Type())
+		if p.MaxStructFields > 0 && len(fields) > p.MaxStructFields {
+			fields = fields[:p.MaxStructFields]
+		}
+		for _, f := range fields {
+			fieldPath := path
+			if !f.anonymous {
+				fieldPath = joinPath(path, f.name)
+			}
+			p.walk(v.Field(f.index), fieldPath, s.nested(), visit)
+		}
+
+	case reflect.Map:
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, path) {
+			visit(path, v, s, true)
+			return
+		}
+		defer delete(s.ptrs, ptr)
+		if !visit(path, orig, s, false) {
+			return
+		}
+		keys := v.MapKeys()
+		p.sortReflectValues(keys, v.Type().Key(), s)
+		if p.MaxMapLength > 0 && len(keys) > p.MaxMapLength {
+			keys = keys[:p.MaxMapLength]
+		}
+		for _, key := range keys {
+			var keyPath string
+			if key.Kind() == reflect.String {
+				keyPath = key.String()
+			} else {
+				var b strings.Builder
+				p.fprint(&b, key, s)
+				keyPath = b.String()
+			}
+			p.walk(v.MapIndex(key), joinPath(path, pathKey(keyPath)), s.nested(), visit)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, path) {
+				visit(path, v, s, true)
+				return
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		if !visit(path, orig, s, false) {
+			return
+		}
+		n := v.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength && v.Kind() == reflect.Slice {
+			n = p.MaxSliceLength
+		}
+		for i := 0; i < n; i++ {
+			p.walk(v.Index(i), path+"["+strconv.Itoa(i)+"]", s.nested(), visit)
+		}
+
+	default:
+		visit(path, orig, s, true)
+	}
+}
+
+// isLeafValue returns true if v is printed as a single value
+// without nested paths.
+func (p *Printer) isLeafValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	if implements(v, typeOfPrintable) || implements(v, typeOfPrinterAware) || implements(v, typeOfContext) || implements(v, typeOfValuer) {
+		return true
+	}
+	if isNullValue(v) {
+		return true
+	}
+	switch v.Type() {
+	case typeOfTime, typeOfDuration:
+		return true
+	}
+	if isOpaqueType(v.Type()) || isBigNumberType(v.Type()) || isNetType(v.Type()) {
+		return true
+	}
+	if p.DetectUUIDs && isUUID(v) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return len(exportedFields(v.Type())) == 0
+	case reflect.Map:
+		return v.Len() == 0
+	case reflect.Slice:
+		switch v.Type().Elem() {
+		case typeOfByte, typeOfRune:
+			return true
+		}
+		return v.Len() == 0
+	case reflect.Array:
+		return v.Len() == 0 || p.HexByteArrays && v.Type().Elem().Kind() == reflect.Uint8
+	}
+	return true
+}
+
+// implements returns if v or a pointer to an addressable v
+// implements the interface type iface.
+func implements(v reflect.Value, iface reflect.Type) bool {
+	return v.Type().Implements(iface) || v.CanAddr() && reflect.PtrTo(v.Type()).Implements(iface)
+}
+
+// isNullValue returns if v or a pointer to an addressable v
+// implements Nullable and IsNull returns true.
+// Nil pointers and interfaces are nil but not null,
+// and IsNull is not called for them because it would panic
+// for value receiver methods.
+func isNullValue(v reflect.Value) bool {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return false
+	}
+	nullable, _ := v.Interface().(Nullable)
+	if nullable == nil && v.CanAddr() {
+		nullable, _ = v.Addr().Interface().(Nullable)
+	}
+	return nullable != nil && nullable.IsNull()
+}
+
+// pathKey returns the map key for a path,
+// quoted if it is empty or contains characters
+// of the path syntax that would make the path ambiguous
+func pathKey(key string) string {
+	if key == "" || strings.ContainsAny(key, `.[]"`) {
+		return strconv.Quote(key)
+	}
+	return key
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,125 @@
+package pretty
+
+import (
+	"bytes"
+	"go/token"
+	"reflect"
+	"sync"
+)
+
+// structField is the printing plan for a struct field
+type structField struct {
+	index     int
+	name      string
+	anonymous bool
+	// sepLabel is the field name prefixed with
+	// the separator ';' and followed by ':'
+	sepLabel string
+	// maxLen overrides the Printer limits if > 0
+	maxLen int
+	// expand an interface field even if
+	// Printer.InterfaceFieldsAsTypes is true
+	expand bool
+	// unexported field that has to be made readable
+	unexported bool
+	// stringer prints the String method result of the field
+	stringer bool
+	// set sorts and deduplicates the elements of a slice field
+	set bool
+}
+
+var (
+	// structFieldsCache caches the exported []structField of reflect.Type keys
+	structFieldsCache sync.Map
+	// allFieldsCache caches the []structField
+	// including unexported fields of reflect.Type keys
+	allFieldsCache sync.Map
+)
+
+// exportedFields returns the cached printing plan
+// for the exported fields of the struct type t.
+func exportedFields(t reflect.Type) []structField {
+	return structFields(t, false, &structFieldsCache)
+}
+
+// allFields returns the cached printing plan
+// for the exported and unexported fields of the struct type t.
+func allFields(t reflect.Type) []structField {
+	return structFields(t, true, &allFieldsCache)
+}
+
+func structFields(t reflect.Type, includeUnexported bool, cache *sync.Map) []structField {
+	if cached, ok := cache.Load(t); ok {
+		return cached.([]structField)
+	}
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		exported := token.IsExported(f.Name)
+		if !exported && !includeUnexported {
+			continue
+		}
+		field := structField{
+			index:      i,
+			name:       f.Name,
+			anonymous:  f.Anonymous,
+			sepLabel:   ";" + f.Name + ":",
+			unexported: !exported,
+		}
+		if parseFieldTag(f.Tag, &field) {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	cache.Store(t, fields)
+	return fields
+}
+
+// prepareType fills the struct field caches
+// for t and all types reachable from it,
+// including the unexported fields if includeUnexported is true.
+func prepareType(t reflect.Type, includeUnexported bool, prepared map[reflect.Type]struct{}) {
+	if _, ok := prepared[t]; ok {
+		return
+	}
+	prepared[t] = struct{}{}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		prepareType(t.Elem(), includeUnexported, prepared)
+	case reflect.Map:
+		prepareType(t.Key(), includeUnexported, prepared)
+		prepareType(t.Elem(), includeUnexported, prepared)
+	case reflect.Struct:
+		fields := exportedFields(t)
+		if includeUnexported {
+			fields = allFields(t)
+		}
+		for _, f := range fields {
+			prepareType(t.Field(f.index).Type, includeUnexported, prepared)
+		}
+	}
+}
+
+// Prepare returns a function that pretty prints values
+// of type T like p.Sprint without indentation.
+// Prepare fills the package wide cache of struct field plans
+// for T and all struct types reachable from it up front
+// instead of on the first print of each type,
+// and the returned function reuses its buffers between calls.
+// Apart from that, values are printed exactly like with p.Sprint,
+// and types behind interfaces are not prepared.
+// Go does not support generic methods, so Prepare is a function
+// taking the Printer as argument.
+func Prepare[T any](p *Printer) func(T) string {
+	prepareType(reflect.TypeOf((*T)(nil)).Elem(), p.IncludeUnexported, make(map[reflect.Type]struct{}))
+	buffers := sync.Pool{
+		New: func() any { return new(bytes.Buffer) },
+	}
+	return func(value T) string {
+		buf := buffers.Get().(*bytes.Buffer)
+		defer buffers.Put(buf)
+		buf.Reset()
+		p.fprintIndent(buf, value, nil)
+		return buf.String()
+	}
+}
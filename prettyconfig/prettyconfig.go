@@ -0,0 +1,162 @@
+// Package prettyconfig loads the configuration
+// of a pretty.Printer from a JSON file,
+// so that the printing policy can be shared
+// between services as a config artifact.
+//
+// Example configuration:
+//
+//	{
+//		"printer": {
+//			"MaxStringLength": 100,
+//			"SanitizeForLogs": true
+//		},
+//		"formatters": {
+//			"github.com/domonda/go-types/money.Amount": "type"
+//		},
+//		"redact": [
+//			"(?i)bearer\\s+\\S+"
+//		]
+//	}
+package prettyconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	pretty "github.com/domonda/go-pretty"
+)
+
+// Config is the file format read by Load and Parse
+type Config struct {
+	// Printer holds the exported fields of pretty.Printer
+	// by their Go names. Fields that are not set
+	// keep the default limits of pretty.NewPrinter.
+	Printer json.RawMessage `json:"printer,omitempty"`
+
+	// Formatters maps the full type names like "time.Month"
+	// or "github.com/org/pkg.Type" of types registered with
+	// RegisterTypes to the names of formatters registered
+	// with RegisterFormatter or the builtin formatters
+	// "type" printing only the type name
+	// and "redact" printing pretty.Redacted.
+	Formatters map[string]string `json:"formatters,omitempty"`
+
+	// Redact are regular expressions set as
+	// pretty.Printer.RedactPatterns.
+	Redact []string `json:"redact,omitempty"`
+}
+
+var (
+	registryMtx sync.RWMutex
+	types       = make(map[string]reflect.Type)
+	formatters  = map[string]func(any) string{
+		"type":   func(v any) string { return reflect.TypeOf(v).String() },
+		"redact": func(any) string { return pretty.Redacted },
+	}
+)
+
+// TypeName returns the full type name of t used
+// as key of Config.Formatters, like "github.com/org/pkg.Type"
+// for named types or the result of t.String() for other types.
+func TypeName(t reflect.Type) string {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// RegisterTypes makes types available by their TypeName
+// for formatter registrations in configuration files.
+// Safe for concurrent use.
+func RegisterTypes(ts ...reflect.Type) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	for _, t := range ts {
+		types[TypeName(t)] = t
+	}
+}
+
+// RegisterFormatter makes format available by name
+// for formatter registrations in configuration files,
+// see pretty.Printer.RegisterFormatter.
+// Safe for concurrent use.
+func RegisterFormatter(name string, format func(v any) string) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	formatters[name] = format
+}
+
+// Load reads the configuration file at path
+// and returns the configured Printer.
+// Only JSON files are supported, YAML files
+// have to be converted to JSON before loading.
+func Load(path string) (*pretty.Printer, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", "":
+	default:
+		return nil, fmt.Errorf("unsupported pretty config file format %q, only JSON is supported", filepath.Ext(path))
+	}
+	data, err := os.ReadFile(path) //#nosec G304
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse pretty config %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse returns the Printer configured by
+// the JSON encoded Config in data.
+// Unknown fields, types and formatters are errors.
+func Parse(data []byte) (*pretty.Printer, error) {
+	var config Config
+	if err := decodeStrict(data, &config); err != nil {
+		return nil, err
+	}
+	p := pretty.NewPrinter()
+	if len(config.Printer) > 0 {
+		if err := decodeStrict(config.Printer, p); err != nil {
+			return nil, fmt.Errorf("invalid printer: %w", err)
+		}
+	}
+	for _, pattern := range config.Redact {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern: %w", err)
+		}
+		p.RedactPatterns = append(p.RedactPatterns, re)
+	}
+
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+
+	for typeName, formatterName := range config.Formatters {
+		t, ok := types[typeName]
+		if !ok {
+			return nil, fmt.Errorf("formatter for unregistered type %q", typeName)
+		}
+		format, ok := formatters[formatterName]
+		if !ok {
+			return nil, fmt.Errorf("unknown formatter %q for type %q", formatterName, typeName)
+		}
+		p.RegisterFormatter(t, format)
+	}
+	return p, nil
+}
+
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,70 @@
+package prettyconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type AccountID int
+
+type Token string
+
+func TestLoad(t *testing.T) {
+	RegisterTypes(reflect.TypeOf(AccountID(0)), reflect.TypeOf(Token("")))
+	RegisterFormatter("hash", func(any) string { return "#" })
+
+	type Login struct {
+		Account AccountID
+		Token   Token
+		Header  string
+		Note    string
+	}
+	config := `{
+		"printer": {"MaxStringLength": 8, "OmitZero": true},
+		"formatters": {
+			"github.com/domonda/go-pretty/prettyconfig.AccountID": "hash",
+			"github.com/domonda/go-pretty/prettyconfig.Token": "redact"
+		},
+		"redact": ["Bearer \\S+"]
+	}`
+	path := filepath.Join(t.TempDir(), "pretty.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	value := Login{Account: 42, Token: "secret", Header: "Bearer abc", Note: "a long note"}
+	want := "Login{Account:#;Token:REDACTED;Header:`REDACTED`;Note:`a long n…`}"
+	if got := p.Sprint(value); got != want {
+		t.Errorf("Printer.Sprint() = %v, want %v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "syntax", config: `{`},
+		{name: "unknown key", config: `{"unknown": 1}`},
+		{name: "unknown printer field", config: `{"printer": {"MaxLength": 1}}`},
+		{name: "invalid pattern", config: `{"redact": ["("]}`},
+		{name: "unregistered type", config: `{"formatters": {"pkg.Unknown": "type"}}`},
+		{name: "unknown formatter", config: `{"formatters": {"int": "unknown"}}`},
+	}
+	RegisterTypes(reflect.TypeOf(0))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.config)); err == nil {
+				t.Errorf("Parse(%s) expected error", tt.config)
+			}
+		})
+	}
+	if _, err := Load("pretty.yaml"); err == nil {
+		t.Error("Load() of YAML file expected error")
+	}
+}
@@ -0,0 +1,178 @@
+// Package prettyhtml renders values pretty printed
+// by the pretty package as nested HTML
+// <details> and <summary> elements, so that large structures
+// can be explored interactively in a browser
+// or embedded in debug dashboards.
+package prettyhtml
+
+import (
+	"html"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	pretty "github.com/domonda/go-pretty"
+)
+
+// Style is an optional CSS style element for the
+// class names used in the rendered HTML.
+const Style = `<style>
+.pretty details { margin-left: 1em; }
+.pretty .pretty-leaf { margin-left: 2em; }
+.pretty .pretty-key { font-weight: bold; }
+.pretty .pretty-type, .pretty .pretty-len { color: gray; }
+</style>`
+
+// Sprint returns value rendered as HTML
+// using the default Printer of the pretty package,
+// see SprintWith.
+func Sprint(value any) string {
+	return SprintWith(pretty.Default(), value)
+}
+
+// Fprint writes value rendered as HTML to w
+// using the default Printer of the pretty package,
+// see SprintWith.
+func Fprint(w io.Writer, value any) error {
+	return FprintWith(w, pretty.Default(), value)
+}
+
+// FprintWith writes value rendered as HTML to w
+// using the Printer p, see SprintWith.
+func FprintWith(w io.Writer, p *pretty.Printer, value any) error {
+	_, err := io.WriteString(w, SprintWith(p, value))
+	return err
+}
+
+// SprintWith returns value rendered as HTML
+// using the Printer p.
+// Structs, maps, slices and arrays become <details> elements
+// with the key, type and length in the <summary>
+// and their elements nested inside.
+// The elements are the same as walked by Printer.Walk,
+// so limits like MaxSliceLength are applied.
+// All other values, like strings or implementations of
+// pretty.Printable, are rendered as HTML escaped output
+// of Printer.Sprint in a <code> element.
+// The root element has the class "pretty" and is opened,
+// nested elements are collapsed.
+func SprintWith(p *pretty.Printer, value any) string {
+	var b strings.Builder
+	b.WriteString(`<div class="pretty">`)
+	writeNode(&b, p, "", reflect.ValueOf(value), true, make(map[uintptr]bool))
+	b.WriteString(`</div>`)
+	return b.String()
+}
+
+type child struct {
+	key   string
+	value reflect.Value
+}
+
+// children returns the direct children of v
+// as walked by p
+func children(p *pretty.Printer, v reflect.Value) (result []child) {
+	root := true
+	p.Walk(v.Interface(), func(path string, v reflect.Value) bool {
+		switch {
+		case root:
+			root = false
+			return true
+		case path == "":
+			// Embedded struct with the path of its parent
+			result = append(result, child{key: typeName(v), value: v})
+		default:
+			result = append(result, child{key: path, value: v})
+		}
+		return false
+	})
+	return result
+}
+
+func writeNode(b *strings.Builder, p *pretty.Printer, key string, v reflect.Value, open bool, ancestors map[uintptr]bool) {
+	var kids []child
+	if ptr, ok := pointer(v); ok && ancestors[ptr] {
+		token := p.CircularRefToken
+		if token == "" {
+			token = pretty.CircularRef
+		}
+		writeLeaf(b, key, token)
+		return
+	} else if ok {
+		ancestors[ptr] = true
+		defer delete(ancestors, ptr)
+	}
+	if v.IsValid() && v.CanInterface() {
+		kids = children(p, v)
+	}
+	if len(kids) == 0 {
+		var str string
+		if v.IsValid() && v.CanInterface() {
+			str = p.Sprint(v.Interface())
+		} else {
+			str = p.Sprint(nil)
+		}
+		writeLeaf(b, key, str)
+		return
+	}
+
+	if open {
+		b.WriteString(`<details open><summary>`)
+	} else {
+		b.WriteString(`<details><summary>`)
+	}
+	if key != "" {
+		b.WriteString(`<span class="pretty-key">` + html.EscapeString(key) + `</span> `)
+	}
+	b.WriteString(`<span class="pretty-type">` + html.EscapeString(typeName(v)) + `</span>`)
+	if elem := deref(v); elem.Kind() == reflect.Map || elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+		b.WriteString(` <span class="pretty-len">len ` + strconv.Itoa(elem.Len()) + `</span>`)
+	}
+	b.WriteString(`</summary>`)
+	for _, kid := range kids {
+		writeNode(b, p, kid.key, kid.value, false, ancestors)
+	}
+	b.WriteString(`</details>`)
+}
+
+func writeLeaf(b *strings.Builder, key, str string) {
+	b.WriteString(`<div class="pretty-leaf">`)
+	if key != "" {
+		b.WriteString(`<span class="pretty-key">` + html.EscapeString(key) + `</span>: `)
+	}
+	b.WriteString(`<code>` + html.EscapeString(str) + `</code></div>`)
+}
+
+// pointer returns the pointer of v if it
+// can be part of a circular reference
+func pointer(v reflect.Value) (uintptr, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if !v.IsNil() {
+			return v.Pointer(), true
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			return pointer(v.Elem())
+		}
+	}
+	return 0, false
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+func typeName(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.Type().String()
+}
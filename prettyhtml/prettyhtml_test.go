@@ -0,0 +1,51 @@
+package prettyhtml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSprint(t *testing.T) {
+	type Item struct {
+		Name  string
+		Price float64
+	}
+	type Order struct {
+		ID    int
+		Items []Item
+		Tags  map[string]int
+	}
+	order := &Order{
+		ID:    7,
+		Items: []Item{{Name: "<Pen>", Price: 1.5}},
+		Tags:  map[string]int{"a": 1},
+	}
+	want := `<div class="pretty"><details open><summary><span class="pretty-type">*prettyhtml.Order</span></summary>` +
+		`<div class="pretty-leaf"><span class="pretty-key">ID</span>: <code>7</code></div>` +
+		`<details><summary><span class="pretty-key">Items</span> <span class="pretty-type">[]prettyhtml.Item</span> <span class="pretty-len">len 1</span></summary>` +
+		`<details><summary><span class="pretty-key">[0]</span> <span class="pretty-type">prettyhtml.Item</span></summary>` +
+		"<div class=\"pretty-leaf\"><span class=\"pretty-key\">Name</span>: <code>`&lt;Pen&gt;`</code></div>" +
+		`<div class="pretty-leaf"><span class="pretty-key">Price</span>: <code>1.5</code></div>` +
+		`</details></details>` +
+		`<details><summary><span class="pretty-key">Tags</span> <span class="pretty-type">map[string]int</span> <span class="pretty-len">len 1</span></summary>` +
+		`<div class="pretty-leaf"><span class="pretty-key">a</span>: <code>1</code></div>` +
+		`</details></details></div>`
+	if got := Sprint(order); got != want {
+		t.Errorf("Sprint() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, want := Sprint("x"), "<div class=\"pretty\"><div class=\"pretty-leaf\"><code>`x`</code></div></div>"; got != want {
+		t.Errorf("Sprint() leaf = %s, want %s", got, want)
+	}
+
+	type Node struct {
+		Name string
+		Next *Node
+	}
+	node := &Node{Name: "a"}
+	node.Next = node
+	got := Sprint(node)
+	if !strings.Contains(got, `<span class="pretty-key">Next</span>: <code>CIRCULAR_REF</code>`) {
+		t.Errorf("Sprint() circular = %s", got)
+	}
+}
@@ -0,0 +1,55 @@
+// Package prettytest provides test helpers for
+// code that logs values with the pretty package.
+package prettytest
+
+import (
+	"regexp"
+	"testing"
+
+	pretty "github.com/domonda/go-pretty"
+)
+
+// DefaultSecretPatterns are the regular expressions used by
+// AssertNoSecrets if no patterns are passed.
+// They match typical credentials like private keys,
+// cloud provider and API tokens, JWTs, bearer tokens
+// and non empty string values of fields or map keys
+// named like passwords, secrets, tokens or API keys.
+var DefaultSecretPatterns = []string{
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+	`\bAKIA[0-9A-Z]{16}\b`,
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}`,
+	`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`,
+	`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{8,}`,
+	"(?i)(password|passwd|secret|api_?key|access_?token|auth_?token)`?:\\s*`[^`]+`",
+}
+
+// AssertNoSecrets renders value with the default Printer
+// of the pretty package as used in production
+// and reports an error on t for every regular expression
+// of patterns that matches the output.
+// If no patterns are passed, then DefaultSecretPatterns are used.
+// Invalid patterns are reported as fatal errors.
+// Returns true if no pattern matched.
+func AssertNoSecrets(t testing.TB, value any, patterns ...string) bool {
+	t.Helper()
+
+	if len(patterns) == 0 {
+		patterns = DefaultSecretPatterns
+	}
+	output := pretty.Default().Sprint(value)
+	ok := true
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			t.Fatalf("invalid secret pattern %q: %s", pattern, err)
+			return false
+		}
+		if match := re.FindString(output); match != "" {
+			t.Errorf("pretty output contains %q matching secret pattern %q", match, pattern)
+			ok = false
+		}
+	}
+	return ok
+}
@@ -16,29 +16,140 @@ package pretty
 
 import (
 	"io"
+	"reflect"
 )
 
 // Println pretty prints a value to os.Stdout followed by a newline
 func Println(value any, indent ...string) {
-	DefaultPrinter.Println(value, indent...)
+	Default().Println(value, indent...)
 }
 
 // Print pretty prints a value to os.Stdout
 func Print(value any, indent ...string) {
-	DefaultPrinter.Print(value, indent...)
+	Default().Print(value, indent...)
+}
+
+// Eprintln pretty prints a value to os.Stderr followed by a newline
+func Eprintln(value any, indent ...string) {
+	Default().Eprintln(value, indent...)
+}
+
+// Eprint pretty prints a value to os.Stderr
+func Eprint(value any, indent ...string) {
+	Default().Eprint(value, indent...)
+}
+
+// Var pretty prints a value to os.Stdout
+// as "name = value" followed by a newline
+func Var(name string, value any) {
+	Default().Var(name, value)
+}
+
+// Vars pretty prints pairs of names and values to os.Stdout
+// as "name = value" lines
+func Vars(pairs ...any) {
+	Default().Vars(pairs...)
 }
 
 // Fprint pretty prints a value to a io.Writer
 func Fprint(w io.Writer, value any, indent ...string) {
-	DefaultPrinter.Fprint(w, value, indent...)
+	Default().Fprint(w, value, indent...)
 }
 
 // Fprint pretty prints a value to a io.Writer followed by a newline
 func Fprintln(w io.Writer, value any, indent ...string) {
-	DefaultPrinter.Fprintln(w, value, indent...)
+	Default().Fprintln(w, value, indent...)
 }
 
 // Sprint pretty prints a value to a string
 func Sprint(value any, indent ...string) string {
-	return DefaultPrinter.Sprint(value, indent...)
+	return Default().Sprint(value, indent...)
+}
+
+// SprintWithPaths pretty prints all leaf values of value
+// and returns them mapped by their path like "Sub.Map.key".
+func SprintWithPaths(value any) map[string]string {
+	return Default().SprintWithPaths(value)
+}
+
+// FprintMapTable pretty prints a map with struct keys
+// as aligned text table to w.
+func FprintMapTable(w io.Writer, m any) {
+	Default().FprintMapTable(w, m)
+}
+
+// SprintMapTable pretty prints a map with struct keys
+// as aligned text table to a string.
+func SprintMapTable(m any) string {
+	return Default().SprintMapTable(m)
+}
+
+// FprintTable pretty prints a slice of structs or maps
+// as aligned text table to w, see Printer.FprintTable.
+func FprintTable(w io.Writer, slice any) {
+	Default().FprintTable(w, slice)
+}
+
+// SprintTable pretty prints a slice of structs or maps
+// as aligned text table to a string, see Printer.FprintTable.
+func SprintTable(slice any) string {
+	return Default().SprintTable(slice)
+}
+
+// DiffJSON unmarshals the JSON documents a and b
+// and returns the structural differences as lines
+// of pretty printed values sorted by path.
+func DiffJSON(a, b []byte) (string, error) {
+	return Default().DiffJSON(a, b)
+}
+
+// FprintEach pretty prints every element of a slice or array
+// without the surrounding brackets to w, separated by sep
+// or on separate lines if sep is empty.
+func FprintEach(w io.Writer, slice any, sep string) {
+	Default().FprintEach(w, slice, sep)
+}
+
+// ToMap converts value to a generic map for structured logging
+// using the same traversal rules as the printing of value.
+func ToMap(value any) map[string]any {
+	return Default().ToMap(value)
+}
+
+// OTelAttributes flattens value into attributes sorted by key
+// that can be converted to OpenTelemetry attributes.
+func OTelAttributes(value any, prefix string) []Attribute {
+	return Default().OTelAttributes(value, prefix)
+}
+
+// DiffIgnoring returns the differences between the leaf values
+// of a and b skipping the paths matching any of the ignore paths.
+func DiffIgnoring(a, b any, ignore ...string) string {
+	return Default().DiffIgnoring(a, b, ignore...)
+}
+
+// EqualIgnoring returns true if a and b have no differences
+// except for the paths matching any of the ignore paths.
+func EqualIgnoring(a, b any, ignore ...string) bool {
+	return Default().EqualIgnoring(a, b, ignore...)
+}
+
+// Walk calls fn for value and all values nested in it
+// using the same traversal as the printing of value.
+// If fn returns false for a struct, map, slice or array,
+// then its elements are not walked.
+func Walk(value any, fn func(path string, v reflect.Value) bool) {
+	Default().Walk(value, fn)
+}
+
+// SprintNonDefault pretty prints only the exported struct fields
+// of value that differ from the same fields of prototype.
+func SprintNonDefault(value, prototype any) string {
+	return Default().SprintNonDefault(value, prototype)
+}
+
+// DiffMaps returns the added, removed and changed keys
+// of the maps a and b as lines of pretty printed values.
+func DiffMaps(a, b map[string]any) string {
+	return Default().DiffMaps(a, b)
 }
@@ -0,0 +1,111 @@
+package pretty
+
+import (
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// FprintCSV writes a slice or array as CSV
+// using the default Printer, see Printer.FprintCSV.
+func FprintCSV(w io.Writer, slice any) error {
+	return Default().FprintCSV(w, slice)
+}
+
+// SprintCSV returns a slice or array as CSV
+// using the default Printer, see Printer.FprintCSV.
+func SprintCSV(slice any) (string, error) {
+	return Default().SprintCSV(slice)
+}
+
+// SprintCSV returns a slice or array as CSV,
+// see Printer.FprintCSV.
+func (p *Printer) SprintCSV(slice any) (string, error) {
+	var b strings.Builder
+	err := p.FprintCSV(&b, slice)
+	return b.String(), err
+}
+
+// FprintCSV writes a slice or array of structs or maps as CSV to w
+// with a header line and one line per element,
+// for example to hand debug dumps to non-developers.
+// The elements are flattened to their leaf values like with Walk,
+// so nested struct fields and map keys become columns
+// with paths like "Address.City" in the order of their first occurrence.
+// Cells contain strings unquoted and all other values pretty printed
+// with the limits like MaxStringLength applied,
+// paths missing in an element are empty cells.
+// Elements are truncated to MaxSliceLength.
+// A value that is not a slice or array is written as single line.
+func (p *Printer) FprintCSV(w io.Writer, slice any) error {
+	v := reflect.ValueOf(slice)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	elems := []reflect.Value{v}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		n := v.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength {
+			n = p.MaxSliceLength
+		}
+		elems = make([]reflect.Value, n)
+		for i := range elems {
+			elems[i] = v.Index(i)
+		}
+	}
+
+	var (
+		header  []string
+		columns = make(map[string]int)
+		rows    = make([]map[string]string, len(elems))
+	)
+	for i, elem := range elems {
+		rows[i] = make(map[string]string)
+		p.walk(elem, "", p.newPrintState(), func(path string, v reflect.Value, s printState, leaf bool) bool {
+			if !leaf {
+				return true
+			}
+			if path == "" {
+				path = "Value"
+			}
+			if _, ok := columns[path]; !ok {
+				columns[path] = len(header)
+				header = append(header, path)
+			}
+			rows[i][path] = p.csvCell(v, s)
+			return false
+		})
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for col, path := range header {
+			record[col] = row[path]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell returns the leaf value v as CSV cell
+func (p *Printer) csvCell(v reflect.Value, s printState) string {
+	elem := v
+	for (elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface) && !elem.IsNil() {
+		elem = elem.Elem()
+	}
+	if elem.Kind() == reflect.String && !p.hasCustomFormat(elem) && !(p.UseStringer && implements(elem, typeOfStringer)) {
+		return p.toMapString(elem.String())
+	}
+	if !v.IsValid() {
+		return p.nilToken()
+	}
+	return p.sprintState(v, s)
+}
@@ -0,0 +1,206 @@
+package pretty
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SprintDOT returns the object graph of value as Graphviz DOT
+// using the default Printer, see Printer.FprintDOT.
+func SprintDOT(value any) string {
+	return Default().SprintDOT(value)
+}
+
+// SprintDOT returns the object graph of value as Graphviz DOT,
+// see Printer.FprintDOT.
+func (p *Printer) SprintDOT(value any) string {
+	var b strings.Builder
+	p.FprintDOT(&b, value)
+	return b.String()
+}
+
+// FprintDOT writes the object graph of value as Graphviz DOT digraph to w
+// for visualizing tangled in-memory data with tools like "dot -Tsvg".
+// Structs, maps, slices and arrays become record nodes
+// labeled with their type and one row per field or element.
+// Rows of values that are printed as a whole, like strings or time.Time,
+// contain the pretty printed value, and rows of nested structs,
+// maps, slices and arrays have an edge to the node of the nested value.
+// Values referenced by pointers, maps and slices are only added once,
+// nodes referenced multiple times are filled to mark them as shared,
+// and edges of circular references back to a node that contains
+// the referencing value are dashed and red.
+// The limits like MaxSliceLength are applied.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) FprintDOT(w io.Writer, value any) {
+	g := &dotGraph{
+		p:        p,
+		ids:      make(map[dotKey]string),
+		incoming: make(map[string]int),
+		onStack:  make(map[string]bool),
+	}
+	v := reflect.ValueOf(value)
+	if id, ok := g.node(v, p.newPrintState()); !ok {
+		// Graph with a single node for leaf values
+		g.nodes = append(g.nodes, dotNode{id: "n0", label: dotEscape(p.Sprint(value))})
+	} else {
+		g.incoming[id]++
+	}
+
+	io.WriteString(w, "digraph {\n\tnode [shape=record];\n")
+	for _, n := range g.nodes {
+		io.WriteString(w, "\t"+n.id+" [label=\""+n.label+"\"")
+		if g.incoming[n.id] > 1 {
+			io.WriteString(w, ",style=filled,fillcolor=lightyellow")
+		}
+		io.WriteString(w, "];\n")
+	}
+	for _, edge := range g.edges {
+		io.WriteString(w, "\t"+edge+";\n")
+	}
+	io.WriteString(w, "}\n")
+}
+
+// dotKey identifies a referenced value by its address and type
+// because a struct and its first field have the same address
+type dotKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type dotNode struct {
+	id    string
+	label string
+}
+
+type dotGraph struct {
+	p        *Printer
+	nodes    []dotNode
+	edges    []string
+	ids      map[dotKey]string
+	incoming map[string]int
+	onStack  map[string]bool
+}
+
+// node adds the node of v and its nested nodes to the graph
+// and returns its id or false if v is not a node
+// but written as value in the row of its parent.
+func (g *dotGraph) node(v reflect.Value, s printState) (id string, ok bool) {
+	var key *dotKey
+	for v.IsValid() && !g.p.isStructuredLeaf(v) && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return "", false
+		}
+		if v.Kind() == reflect.Ptr {
+			key = &dotKey{v.Pointer(), v.Type().Elem()}
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || g.p.isStructuredLeaf(v) {
+		return "", false
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return "", false
+		}
+		key = &dotKey{v.Pointer(), v.Type()}
+	case reflect.Struct, reflect.Array:
+	default:
+		return "", false
+	}
+	if key != nil {
+		if id, ok := g.ids[*key]; ok {
+			return id, true
+		}
+	}
+
+	id = "n" + strconv.Itoa(len(g.nodes))
+	if key != nil {
+		g.ids[*key] = id
+	}
+	index := len(g.nodes)
+	g.nodes = append(g.nodes, dotNode{id: id})
+	g.onStack[id] = true
+	defer delete(g.onStack, id)
+
+	rows := []string{dotEscape(v.Type().String())}
+	addRow := func(name string, elem reflect.Value, s printState) {
+		child, ok := g.node(elem, s)
+		if !ok {
+			rows = append(rows, dotEscape(name)+": "+dotEscape(g.p.sprintState(elem, s)))
+			return
+		}
+		port := "f" + strconv.Itoa(len(rows))
+		rows = append(rows, "<"+port+"> "+dotEscape(name))
+		g.incoming[child]++
+		edge := id + ":" + port + " -> " + child
+		if g.onStack[child] {
+			edge += " [style=dashed,color=red]"
+		}
+		g.edges = append(g.edges, edge)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := exportedFields(v.Type())
+		omitted := 0
+		if g.p.MaxStructFields > 0 && len(fields) > g.p.MaxStructFields {
+			omitted = len(fields) - g.p.MaxStructFields
+			fields = fields[:g.p.MaxStructFields]
+		}
+		for _, f := range fields {
+			field := v.Field(f.index)
+			if g.p.OmitZero && field.IsZero() {
+				continue
+			}
+			addRow(f.name, field, s.field(f.name))
+		}
+		if omitted > 0 {
+			rows = append(rows, g.p.ellipsis()+"(+"+strconv.Itoa(omitted)+" fields)")
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		g.p.sortReflectValues(keys, v.Type().Key(), s)
+		n := len(keys)
+		if g.p.MaxMapLength > 0 && n > g.p.MaxMapLength {
+			n = g.p.MaxMapLength
+		}
+		for _, k := range keys[:n] {
+			addRow(g.p.sprintState(k, s), v.MapIndex(k), s.key(k))
+		}
+		if n < len(keys) {
+			rows = append(rows, g.p.ellipsis()+"+"+strconv.Itoa(len(keys)-n)+" more")
+		}
+
+	case reflect.Slice, reflect.Array:
+		n := v.Len()
+		if g.p.MaxSliceLength > 0 && n > g.p.MaxSliceLength {
+			n = g.p.MaxSliceLength
+		}
+		for i := 0; i < n; i++ {
+			addRow("["+strconv.Itoa(i)+"]", v.Index(i), s.index(i))
+		}
+		if n < v.Len() {
+			rows = append(rows, g.p.ellipsis()+"+"+strconv.Itoa(v.Len()-n)+" more")
+		}
+	}
+	g.nodes[index].label = "{" + strings.Join(rows, "|") + "}"
+	return id, true
+}
+
+// dotEscape escapes str for a quoted DOT record label
+func dotEscape(str string) string {
+	var b strings.Builder
+	for _, r := range str {
+		if strings.ContainsRune(`\"{}|<>`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,16 +1,24 @@
 package pretty
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
-	"go/token"
 	"io"
+	"math"
 	"os"
 	"reflect"
+	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unicode/utf8"
+	"unsafe"
 )
 
 // Printable can be implemented to customize the pretty printing of a type.
@@ -19,8 +27,22 @@ type Printable interface {
 	PrettyPrint(io.Writer)
 }
 
-// Nullable can be implemented to print "null" instead of
+// PrinterAware can be implemented to customize the pretty printing
+// of a type with the Printer that is used to print it,
+// so that nested values can be printed with p.Fprint
+// honoring the caller's limits like MaxStringLength.
+// PrinterAware takes precedence over Printable.
+type PrinterAware interface {
+	// PrettyPrintWith prints the implementation's data with p
+	PrettyPrintWith(p *Printer, w io.Writer)
+}
+
+// Nullable can be implemented to print "null"
+// or the Printer.NullToken instead of
 // the representation of the underlying type's value.
+// Implementations of driver.Valuer like sql.NullString
+// are printed as null if their Value method returns nil
+// and else as the returned value.
 type Nullable interface {
 	// IsNull returns true if the implementing value is considered null.
 	IsNull() bool
@@ -42,11 +64,567 @@ type Printer struct {
 	// Longer slices will be truncated with an ellipsis rune as last element.
 	// A value <= 0 will disable truncating.
 	MaxSliceLength int
+
+	// MaxMapLength is the maximum number of printed map entries.
+	// Longer maps will be truncated after the sorted entries
+	// with an ellipsis and the number of omitted entries like …+3 more.
+	// A value <= 0 will disable truncating.
+	MaxMapLength int
+
+	// MaxStructFields is the maximum number of fields
+	// printed for a struct followed by …(+K fields)
+	// for the K omitted fields.
+	// A value <= 0 will disable truncating.
+	MaxStructFields int
+
+	// AppendStructErrors appends the result of the Error method
+	// as additional field err to structs with exported fields
+	// that implement the error interface.
+	// Structs without exported fields are always printed as error.
+	AppendStructErrors bool
+
+	// HexByteArrays prints byte arrays like checksums
+	// as lowercase hex string wrapped in the type name,
+	// for example Hash(9f86d0…).
+	// The hex string is truncated to MaxStringLength.
+	HexByteArrays bool
+
+	// DetectUUIDs prints 16 byte arrays and types with a
+	// UUID method returning a 16 byte array in the
+	// canonical UUID form wrapped in the type name,
+	// for example UUID(6ba7b810-9dad-11d1-80b4-00c04fd430c8).
+	// Takes precedence over HexByteArrays.
+	// Use RegisterFormatter for other ID-like array types.
+	DetectUUIDs bool
+
+	// Parallel renders the elements of top-level slices,
+	// arrays and maps concurrently into separate buffers
+	// that are joined in order to the deterministic result.
+	// Useful for very large values on multi-core machines.
+	Parallel bool
+
+	// InternStringsMinLength enables interning of strings
+	// with at least this length if the value is greater zero.
+	// The first occurrence of such a string is printed fully,
+	// repeated occurrences are printed as reference like @str1
+	// and a legend of all referenced strings is appended to the output.
+	// Interning disables the Parallel option.
+	InternStringsMinLength int
+
+	// StrictSingleLine escapes newlines and carriage returns
+	// written by Printable implementations as \n and \r
+	// so that the output is guaranteed to be a single line
+	// if no indent is used.
+	StrictSingleLine bool
+
+	// CircularRefToken is printed instead of a circular data reference.
+	// If empty, then the CircularRef constant is used.
+	CircularRefToken string
+
+	// CircularRefPath appends the path of the first occurrence
+	// of a circularly referenced value to the CircularRefToken
+	// like CIRCULAR_REF(.Parent.Children[0]).
+	// The top-level value has the path ".".
+	CircularRefPath bool
+
+	// NilToken is printed for nil values.
+	// If empty, then "nil" is used.
+	NilToken string
+
+	// NullToken is printed for Nullable values that are null.
+	// If empty, then "null" is used.
+	NullToken string
+
+	// ShrinkIndentDepth is the nesting depth of indented output
+	// after which ShrinkIndent is used instead of the indent argument.
+	// A value <= 0 disables shrinking.
+	ShrinkIndentDepth int
+
+	// ShrinkIndent is used per nesting level deeper than ShrinkIndentDepth.
+	// Use for example a single space or "| " for guides.
+	ShrinkIndent string
+
+	// DetectUnixTimestamps annotates int64 and uint64 values
+	// in the plausible range of Unix seconds or milliseconds
+	// between the years 2000 and 2100 with the corresponding
+	// UTC time in parentheses, like 1594728574(2020-07-14T12:09:34Z).
+	DetectUnixTimestamps bool
+
+	// Now returns the current time used for example
+	// to print the remaining time until a context deadline.
+	// If nil, then time.Now is used.
+	// Can be set to make tests of printing code deterministic.
+	Now func() time.Time
+
+	// TimeLayout is the layout passed to time.Time.Format
+	// for printing time.Time values, for example time.RFC3339.
+	// If empty, then the result of time.Time.String is printed.
+	TimeLayout string
+
+	// TimeLocation converts time.Time values to
+	// the location like time.UTC or time.Local before printing.
+	// If nil, then the location of the value is kept.
+	TimeLocation *time.Location
+
+	// StripMonotonic strips the monotonic clock reading
+	// like m=+0.000012345 from printed time.Time values,
+	// which is only printed if TimeLayout is empty.
+	StripMonotonic bool
+
+	// SanitizeForLogs removes ANSI escape sequences,
+	// other terminal control characters and Unicode
+	// bidirectional formatting characters from printed strings
+	// so that untrusted input can't manipulate terminals or log viewers.
+	SanitizeForLogs bool
+
+	// MetaRunes substitutes the non-ASCII meta characters
+	// emitted by the printer like the ellipsis … of truncated values
+	// if not nil, see ASCIIMetaRunes.
+	MetaRunes *MetaRunes
+
+	// Brackets are the two grouping characters used for structs and maps,
+	// for example "()" or "<>" to avoid confusion with JSON in mixed logs.
+	// If empty, then "{}" is used.
+	// Indented output will also indent other occurrences
+	// of the brackets outside of strings, see IndentBrackets.
+	Brackets string
+
+	// EmptyStringToken is printed instead of `` for empty strings
+	// to distinguish them clearly from null values, for example `""(empty)`.
+	// If empty, then empty strings are printed as ``.
+	EmptyStringToken string
+
+	// InlineMaxWidth is the maximum width in runes of structs and maps
+	// including their brackets that will be kept on a single line
+	// in indented output, like Map: {`a`: 1; `b`: 2}.
+	// Slices are printed on a single line, see MaxLineWidth.
+	// A value <= 0 expands all non empty structs and maps.
+	InlineMaxWidth int
+
+	// MaxLineWidth is the maximum width in runes of lines
+	// in indented output used to lay out slices and arrays
+	// of short scalar elements that don't fit on their line
+	// in aligned columns like the ls command, for example:
+	//   Tags: [
+	//     `alpha`, `beta`,  `gamma`,
+	//     `delta`, `omega`
+	//   ]
+	// A value <= 0 prints all slices on a single line.
+	MaxLineWidth int
+
+	// FitLineWidth prints values that are printed without
+	// indent arguments on a single line if they fit into the line width,
+	// else indented with two spaces per level, keeping nested
+	// structs, maps and slices on their line if they fit.
+	// The line width is MaxLineWidth if > 0, else the width of
+	// the terminal written to or the COLUMNS environment variable,
+	// else DefaultLineWidth.
+	FitLineWidth bool
+
+	// Colors highlights type names, field names, literals
+	// and tokens like nil with ANSI escape sequences
+	// in the output of the functions writing to an io.Writer
+	// like Print, Println and Fprint if not nil.
+	// Colors are only used if the writer is a terminal
+	// and the environment variable NO_COLOR is not set
+	// and TERM is not "dumb", see ForceColor and DisableColor.
+	// The hook set with SetGlobalHook receives the uncolored output.
+	// See DefaultColorScheme.
+	Colors *ColorScheme
+
+	// ForceColor uses Colors for all writers
+	// ignoring the terminal and environment detection.
+	ForceColor bool
+
+	// DisableColor disables Colors, also if ForceColor is set.
+	DisableColor bool
+
+	// TruncationSidecar is called after printing a value
+	// that was truncated because of the configured limits
+	// with a JSON array of Truncation objects listing
+	// the truncated paths with their original lengths.
+	// Setting it disables the Parallel option.
+	TruncationSidecar func(sidecar []byte)
+
+	// OnTruncatedValue is called for every value that is truncated
+	// because of the configured limits with the path of the value
+	// like "Sub.Items[0].Name" and the original value,
+	// for example to persist the complete originals
+	// of audit logs that keep only the truncated preview.
+	// For pointer fields v is the pointer.
+	// Setting it disables the Parallel option.
+	OnTruncatedValue func(path string, v reflect.Value)
+
+	// KeysOnly prints only the sorted keys of maps
+	// at or beyond the nesting depth KeysOnlyDepth
+	// like {keys:`a`,`b`,`c`} without their values.
+	// The keys are truncated to MaxSliceLength.
+	KeysOnly bool
+
+	// KeysOnlyDepth is the nesting depth starting at 0
+	// for the top-level value from which on KeysOnly applies.
+	KeysOnlyDepth int
+
+	// InvalidUTF8 defines how strings with invalid UTF-8
+	// sequences are printed, see InvalidUTF8Mode.
+	InvalidUTF8 InvalidUTF8Mode
+
+	// DetectJSONStrings prints strings containing a JSON object
+	// or array as decoded JSON value prefixed with JSON, like JSON{`a`:1}.
+	// Error messages ending with a JSON object or array,
+	// typical for HTTP client errors, are printed
+	// as error{Message:`prefix`;JSON:{…}} so that the JSON
+	// is expanded beneath the message in indented output.
+	DetectJSONStrings bool
+
+	// ShowIndexes labels the elements of slices and arrays
+	// with their index like [0]: in indented output
+	// so that every element is printed on its own line.
+	ShowIndexes bool
+
+	// InterfaceFieldsAsTypes prints struct fields of interface types
+	// with methods, like an embedded http.RoundTripper,
+	// only as the type name of their dynamic value.
+	// Fields of type error or any and fields tagged
+	// with `pretty:"expand"` are still printed completely.
+	InterfaceFieldsAsTypes bool
+
+	// ShowNamedTypeUnits wraps values of named numeric types
+	// in their type name like Celsius(21.5) to show the unit
+	// like it's done for time.Duration.
+	ShowNamedTypeUnits bool
+
+	// MaxDepth is the maximum nesting depth of printed
+	// structs, maps, slices and arrays starting at 1
+	// for the top-level value.
+	// Deeper values are printed as placeholder
+	// like Type{…} or […] instead of their elements.
+	// A value <= 0 disables the limit.
+	MaxDepth int
+
+	// RunesAsChars prints values of type rune, or any other
+	// type with int32 kind that doesn't implement fmt.Stringer,
+	// as quoted character like 'A' with escaping of non-printables.
+	// Invalid code points are still printed as number.
+	RunesAsChars bool
+
+	// MaxTotalLength is the maximum length in bytes
+	// of the complete output of a printed value.
+	// Longer output is cut at a valid UTF-8 position
+	// and ends with TotalLengthMarker within the budget.
+	// A value <= 0 disables the limit.
+	MaxTotalLength int
+
+	// OmitZero skips struct fields with the zero value
+	// of their type like empty strings, nil maps and 0 ints.
+	OmitZero bool
+
+	// IncludeUnexported prints unexported struct fields
+	// by reading them with package unsafe,
+	// useful for debugging third-party types.
+	// The fields are never modified.
+	IncludeUnexported bool
+
+	// SortSlices sorts the elements of slices before printing
+	// for stable output of slices with nondeterministic order.
+	// Elements are compared with SliceLess if not nil,
+	// else like map keys with the < operator for basic types
+	// or by their pretty printed representation.
+	// Slices are sorted before they are truncated to MaxSliceLength.
+	SortSlices bool
+
+	// SliceLess is used by SortSlices to compare elements if not nil.
+	SliceLess func(a, b any) bool
+
+	// FloatFormat defines how floating point numbers
+	// and the parts of complex numbers are printed.
+	// The zero value uses the default fmt formatting.
+	FloatFormat FloatFormat
+
+	// FloatSpecials prints the special float values NaN, +Inf and -Inf
+	// as double quoted strings "NaN", "+Inf" and "-Inf"
+	// so that JSON consumers of logs don't break on them.
+	// Negative zero is always printed as the number -0.
+	FloatSpecials bool
+
+	// DurationFormat defines how time.Duration values
+	// are printed, see DurationFormatMode.
+	DurationFormat DurationFormatMode
+
+	// UseStringer prints values of types implementing fmt.Stringer,
+	// but not Printable or error, as the type name
+	// followed by the quoted result of the String method
+	// in parentheses, like Version(`v1.2`),
+	// instead of reflecting over their fields or underlying value.
+	UseStringer bool
+
+	// JSONValueMode prints dynamic JSON values of type
+	// map[string]any and []any like they are decoded
+	// by encoding/json in JSON syntax with sorted keys,
+	// for example {"a":1,"b":[true,null]}.
+	// In indented output object members are separated
+	// by semicolons so that every member gets its own line.
+	JSONValueMode bool
+
+	// UseJSONMarshaler prints values implementing json.Marshaler,
+	// but not Printable, as their decoded JSON.
+	// Objects and arrays are prefixed with JSON like JSON{`a`:1}
+	// and a JSON null is printed as the NullToken.
+	// Values that fail to marshal are printed as usual.
+	UseJSONMarshaler bool
+
+	// CollapseWhitespace replaces every run of at least two
+	// whitespace characters in printed strings and errors
+	// with the run length surrounded by single spaces like " (×12) ",
+	// with × replaced by MetaRunes.Times if set,
+	// before truncation is applied, so that whitespace in
+	// HTML or SQL doesn't waste the MaxStringLength budget.
+	CollapseWhitespace bool
+
+	// RedactPatterns are regular expressions whose matches
+	// in printed strings and errors are replaced with Redacted
+	// before truncation is applied.
+	RedactPatterns []*regexp.Regexp
+
+	// TrailingSeparators ends every member line of expanded
+	// structs and maps in indented output with a semicolon,
+	// including the last one, so that line based diffs
+	// of appended members show only the new lines.
+	TrailingSeparators bool
+
+	// RenderCacheSize is the maximum number of rendered strings
+	// of the types registered with RegisterImmutable that are cached.
+	// If zero, then DefaultRenderCacheSize is used.
+	RenderCacheSize int
+
+	// bitmasks registered with RegisterBitmask
+	bitmasks map[reflect.Type][]bitName
+
+	// formatters registered with RegisterFormatter
+	formatters map[reflect.Type]func(any) string
+
+	// immutables registered with RegisterImmutable
+	immutables map[reflect.Type]bool
+
+	// renderCache of the immutables
+	renderCache *renderCache
+
+	// groupDelimiters prints the brackets of containers
+	// as groupOpen and groupClose, see groupPrinter
+	groupDelimiters bool
+}
+
+// FloatFormat defines the strconv.FormatFloat format
+// and precision of printed floating point numbers.
+// Types implementing fmt.Stringer are not affected.
+type FloatFormat struct {
+	// Format is a format of strconv.FormatFloat like 'f', 'e' or 'g'.
+	// Zero uses the default fmt formatting.
+	Format byte
+	// Precision is the number of digits after the decimal point
+	// for 'f' and 'e' or of significant digits for 'g'.
+	// The value -1 uses the smallest number of digits
+	// necessary to represent the value exactly.
+	Precision int
+}
+
+var (
+	// FloatShortest prints floats with the smallest number
+	// of digits necessary to represent the value exactly
+	// using an exponent only for large exponents.
+	FloatShortest = FloatFormat{Format: 'g', Precision: -1}
+
+	// FloatScientific prints floats with an exponent like 1.5e+06.
+	FloatScientific = FloatFormat{Format: 'e', Precision: -1}
+)
+
+// FloatFixed returns a FloatFormat that prints floats
+// without exponent with precision digits after the decimal point.
+func FloatFixed(precision int) FloatFormat {
+	return FloatFormat{Format: 'f', Precision: precision}
+}
+
+// formatTime returns tm formatted with
+// p.TimeLayout, p.TimeLocation and p.StripMonotonic.
+func (p *Printer) formatTime(tm time.Time) string {
+	if p.TimeLocation != nil {
+		tm = tm.In(p.TimeLocation)
+	}
+	if p.StripMonotonic {
+		tm = tm.Round(0)
+	}
+	if p.TimeLayout == "" {
+		return tm.String()
+	}
+	return tm.Format(p.TimeLayout)
+}
+
+// DurationFormatMode defines how time.Duration values are printed.
+type DurationFormatMode int
+
+const (
+	// DurationString prints the result of time.Duration.String
+	// like Duration(`1h2m3.456s`) which is the default.
+	DurationString DurationFormatMode = iota
+	// DurationNanoseconds prints the raw number
+	// of nanoseconds like Duration(3723456000000).
+	DurationNanoseconds
+	// DurationRounded prints the duration rounded to
+	// the two most significant units like Duration(`1h2m`)
+	// omitting zero seconds.
+	DurationRounded
+)
+
+// formatDuration returns d formatted
+// as defined by p.DurationFormat.
+func (p *Printer) formatDuration(d time.Duration) string {
+	switch p.DurationFormat {
+	case DurationNanoseconds:
+		return "Duration(" + strconv.FormatInt(int64(d), 10) + ")"
+	case DurationRounded:
+		abs := d
+		if abs < 0 {
+			abs = -abs
+		}
+		switch {
+		case abs >= time.Hour:
+			str := d.Round(time.Minute).String()
+			return "Duration(`" + strings.TrimSuffix(str, "0s") + "`)"
+		case abs >= time.Minute:
+			d = d.Round(time.Second)
+		case abs >= time.Second:
+			d = d.Round(time.Millisecond)
+		case abs >= time.Millisecond:
+			d = d.Round(time.Microsecond)
+		}
+	}
+	return "Duration(`" + d.String() + "`)"
+}
+
+// InvalidUTF8Mode defines how strings containing
+// invalid UTF-8 sequences are printed.
+type InvalidUTF8Mode int
+
+const (
+	// InvalidUTF8Escape escapes invalid bytes like \xff
+	// which is the default behavior of %#q formatting.
+	InvalidUTF8Escape InvalidUTF8Mode = iota
+	// InvalidUTF8Replace replaces invalid sequences
+	// with the Unicode replacement character U+FFFD.
+	InvalidUTF8Replace
+	// InvalidUTF8Hex prints the string bytes
+	// as lowercase hex like hex(48656c6c6fff).
+	InvalidUTF8Hex
+)
+
+func (p *Printer) circularRefToken() string {
+	if p.CircularRefToken == "" {
+		return CircularRef
+	}
+	return p.CircularRefToken
+}
+
+// circularRef returns the circularRefToken
+// with the path of the first occurrence of the
+// referenced value if p.CircularRefPath is true.
+func (p *Printer) circularRef(firstPath string) string {
+	if !p.CircularRefPath {
+		return p.circularRefToken()
+	}
+	return p.circularRefToken() + "(." + firstPath + ")"
+}
+
+func (p *Printer) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+func (p *Printer) nilToken() string {
+	if p.NilToken == "" {
+		return "nil"
+	}
+	return p.NilToken
+}
+
+func (p *Printer) nullToken() string {
+	if p.NullToken == "" {
+		return "null"
+	}
+	return p.NullToken
+}
+
+// PrettyPrint implements the Printable interface
+// by printing the non zero configuration fields of the Printer
+// and the types registered with RegisterBitmask and RegisterFormatter,
+// for example to log the active configuration.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) PrettyPrint(w io.Writer) {
+	var (
+		plain = Printer{UseStringer: true}
+		v     = reflect.ValueOf(p).Elem()
+		s     = plain.newPrintState()
+		first = true
+		sep   = func() {
+			if !first {
+				io.WriteString(w, ";")
+			}
+			first = false
+		}
+	)
+	io.WriteString(w, "Printer{")
+	for _, f := range exportedFields(v.Type()) {
+		field := v.Field(f.index)
+		if field.IsZero() {
+			continue
+		}
+		sep()
+		io.WriteString(w, f.name+":")
+		plain.fprint(w, field, s)
+	}
+	if len(p.bitmasks) > 0 {
+		types := make([]string, 0, len(p.bitmasks))
+		for t := range p.bitmasks {
+			types = append(types, t.String())
+		}
+		sort.Strings(types)
+		sep()
+		io.WriteString(w, "Bitmasks:["+strings.Join(types, ",")+"]")
+	}
+	if len(p.formatters) > 0 {
+		types := make([]string, 0, len(p.formatters))
+		for t := range p.formatters {
+			types = append(types, t.String())
+		}
+		sort.Strings(types)
+		sep()
+		io.WriteString(w, "Formatters:["+strings.Join(types, ",")+"]")
+	}
+	if len(p.immutables) > 0 {
+		types := make([]string, 0, len(p.immutables))
+		for t := range p.immutables {
+			types = append(types, t.String())
+		}
+		sort.Strings(types)
+		sep()
+		io.WriteString(w, "Immutables:["+strings.Join(types, ",")+"]")
+	}
+	io.WriteString(w, "}")
+}
+
+// String returns the configuration of the Printer
+// as printed by PrettyPrint.
+func (p *Printer) String() string {
+	var b strings.Builder
+	p.PrettyPrint(&b)
+	return b.String()
 }
 
 // Println pretty prints a value to os.Stdout followed by a newline
 func (p *Printer) Println(value any, indent ...string) {
-	endsWithNewLine := p.fprintIndent(os.Stdout, value, indent)
+	endsWithNewLine := p.fprintIndentHooked(os.Stdout, value, indent)
 	if !endsWithNewLine {
 		os.Stdout.Write([]byte{'\n'}) //#nosec G104
 	}
@@ -54,19 +632,29 @@ func (p *Printer) Println(value any, indent ...string) {
 
 // Print pretty prints a value to os.Stdout
 func (p *Printer) Print(value any, indent ...string) {
-	p.fprintIndent(os.Stdout, value, indent)
+	p.fprintIndentHooked(os.Stdout, value, indent)
+}
+
+// Eprintln pretty prints a value to os.Stderr followed by a newline
+func (p *Printer) Eprintln(value any, indent ...string) {
+	p.Fprintln(os.Stderr, value, indent...)
+}
+
+// Eprint pretty prints a value to os.Stderr
+func (p *Printer) Eprint(value any, indent ...string) {
+	p.fprintIndentHooked(os.Stderr, value, indent)
 }
 
 // Fprint pretty prints a value to a io.Writer
 func (p *Printer) Fprint(w io.Writer, value any, indent ...string) {
-	p.fprintIndent(w, value, indent)
+	p.fprintIndentHooked(w, value, indent)
 }
 
 // Fprint pretty prints a value to a io.Writer followed by a newline
 func (p *Printer) Fprintln(w io.Writer, value any, indent ...string) {
-	endsWithNewLine := p.fprintIndent(w, value, indent)
+	endsWithNewLine := p.fprintIndentHooked(w, value, indent)
 	if !endsWithNewLine {
-		os.Stdout.Write([]byte{'\n'}) //#nosec G104
+		w.Write([]byte{'\n'}) //#nosec G104
 	}
 }
 
@@ -77,51 +665,318 @@ func (p *Printer) Sprint(value any, indent ...string) string {
 	return b.String()
 }
 
-type visitedPtrs map[uintptr]struct{}
+// Var pretty prints a value to os.Stdout
+// as "name = value" followed by a newline
+func (p *Printer) Var(name string, value any) {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteString(" = ")
+	p.fprintIndent(&b, value, nil)
+	b.WriteByte('\n')
+	os.Stdout.WriteString(b.String()) //#nosec G104
+}
 
-func (v visitedPtrs) visit(ptr uintptr) (visited bool) {
+// Vars pretty prints pairs of names and values to os.Stdout
+// as "name = value" lines.
+// Names that are not strings are formatted with fmt.Sprint
+// and a missing last value is printed as nil.
+func (p *Printer) Vars(pairs ...any) {
+	for i := 0; i < len(pairs); i += 2 {
+		name, ok := pairs[i].(string)
+		if !ok {
+			name = fmt.Sprint(pairs[i])
+		}
+		var value any
+		if i+1 < len(pairs) {
+			value = pairs[i+1]
+		}
+		p.Var(name, value)
+	}
+}
+
+// printState is passed down the recursive printing of a value
+type printState struct {
+	ptrs  visitedPtrs
+	depth int
+	strs  *stringInterner
+	// path of the printed value, only tracked if trackPath is true
+	path      string
+	trackPath bool
+	truncs    *[]Truncation
+	// onTruncated is Printer.OnTruncatedValue
+	// called with the currently printed value
+	onTruncated func(path string, v reflect.Value)
+	value       reflect.Value
+	// maxLen of the current value from a struct field tag
+	maxLen int
+	// indented is true if the output will be indented
+	indented bool
+}
+
+func (p *Printer) newPrintState() printState {
+	s := printState{ptrs: make(visitedPtrs)}
+	if p.InternStringsMinLength > 0 {
+		s.strs = &stringInterner{ids: make(map[string]int)}
+	}
+	if p.TruncationSidecar != nil {
+		s.truncs = new([]Truncation)
+		s.trackPath = true
+	}
+	if p.OnTruncatedValue != nil {
+		s.onTruncated = p.OnTruncatedValue
+		s.trackPath = true
+	}
+	if p.CircularRefPath {
+		s.trackPath = true
+	}
+	return s
+}
+
+// nested returns the state for printing an element of the current value
+// that does not change the path like an embedded struct
+func (s printState) nested() printState {
+	s.depth++
+	s.maxLen = 0
+	return s
+}
+
+// field returns the state for printing a struct field
+func (s printState) field(name string) printState {
+	s.depth++
+	s.maxLen = 0
+	if s.trackPath {
+		s.path = joinPath(s.path, name)
+	}
+	return s
+}
+
+// index returns the state for printing a slice or array element
+func (s printState) index(i int) printState {
+	s.depth++
+	s.maxLen = 0
+	if s.trackPath {
+		s.path += "[" + strconv.Itoa(i) + "]"
+	}
+	return s
+}
+
+// key returns the state for printing a map key and its value
+func (s printState) key(key reflect.Value) printState {
+	s.depth++
+	s.maxLen = 0
+	if s.trackPath {
+		if key.Kind() == reflect.String {
+			s.path = joinPath(s.path, key.String())
+		} else {
+			s.path = joinPath(s.path, fmt.Sprint(key.Interface()))
+		}
+	}
+	return s
+}
+
+// visitedPtrs maps visited pointers to the path
+// of their first occurrence if paths are tracked
+type visitedPtrs map[uintptr]string
+
+func (v visitedPtrs) visit(ptr uintptr, path string) (visited bool) {
 	if _, visited = v[ptr]; visited {
 		return true
 	}
-	v[ptr] = struct{}{}
+	v[ptr] = path
 	return false
 }
 
 func (p *Printer) fprintIndent(w io.Writer, value any, indent []string) (endsWithNewLine bool) {
+	if p.FitLineWidth && len(indent) == 0 {
+		return p.fprintFitLineWidth(w, value)
+	}
+	return p.fprintIndentLimited(w, value, indent)
+}
+
+func (p *Printer) fprintIndentLimited(w io.Writer, value any, indent []string) (endsWithNewLine bool) {
+	if p.MaxTotalLength > 0 {
+		tw := &totalLengthWriter{max: p.MaxTotalLength, marker: p.totalLengthMarker()}
+		p.fprintIndentUnlimited(tw, value, indent)
+		out := tw.bytes()
+		w.Write(out) //#nosec G104
+		return len(out) > 0 && out[len(out)-1] == '\n'
+	}
+	return p.fprintIndentUnlimited(w, value, indent)
+}
+
+func (p *Printer) fprintIndentUnlimited(w io.Writer, value any, indent []string) (endsWithNewLine bool) {
+	if p.StrictSingleLine && len(indent) == 0 {
+		w = singleLineWriter{w}
+	}
 	switch {
 	case value == nil:
 		if len(indent) > 1 {
 			fmt.Fprint(w, indent[1])
 		}
-		fmt.Fprint(w, "nil")
+		fmt.Fprint(w, p.nilToken())
 		return false
 
 	case len(indent) == 0:
-		p.fprint(w, reflect.ValueOf(value), make(visitedPtrs))
+		if _, ok := w.(io.StringWriter); !ok {
+			// Batch the many small writes of fprint
+			bw := bufio.NewWriter(w)
+			defer bw.Flush() //#nosec G104
+			w = bw
+		}
+		s := p.newPrintState()
+		p.fprint(w, reflect.ValueOf(value), s)
+		if s.strs != nil {
+			s.strs.fprintLegend(w)
+		}
+		p.emitTruncationSidecar(s)
 		return false
 
 	default:
 		var buf bytes.Buffer
-		p.fprint(&buf, reflect.ValueOf(value), make(visitedPtrs))
-		in := Indent(buf.Bytes(), indent[0], indent[1:]...)
+		g := p.groupPrinter()
+		s := g.newPrintState()
+		s.indented = true
+		if p.StrictSingleLine {
+			g.fprint(singleLineWriter{&buf}, reflect.ValueOf(value), s)
+		} else {
+			g.fprint(&buf, reflect.ValueOf(value), s)
+		}
+		if s.strs != nil {
+			s.strs.fprintLegend(&buf)
+		}
+		g.emitTruncationSidecar(s)
+		in := p.replaceGroupDelimiters(indentSource(buf.Bytes(), g.indentOptions(indent[0], indent[1:])))
 		w.Write(in) //#nosec G104
 		return len(in) > 0 && in[len(in)-1] == '\n'
 	}
 }
 
+// isNonEmptyInterface returns if t is an interface type
+// with methods other than the error interface.
+func isNonEmptyInterface(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() > 0 && t != typeOfError
+}
+
+// isNumericKind returns if k is an integer,
+// floating point or complex number kind.
+func isNumericKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Complex128 && k != reflect.Uintptr
+}
+
+// limit returns s.maxLen if set by a struct field tag, else max
+func (s printState) limit(max int) int {
+	if s.maxLen > 0 {
+		return s.maxLen
+	}
+	return max
+}
+
+// indentOptions returns the options for indenting
+// the output of the printer with the passed arguments.
+func (p *Printer) indentOptions(indent string, linePrefix []string) indentOptions {
+	opts := indentOptions{
+		levelIndent: func(int) string { return indent },
+		linePrefix:  linePrefix,
+	}
+	if p.ShrinkIndentDepth > 0 {
+		opts.levelIndent = func(level int) string {
+			if level > p.ShrinkIndentDepth {
+				return p.ShrinkIndent
+			}
+			return indent
+		}
+	}
+	if p.groupDelimiters {
+		opts.open, opts.close = groupOpen, groupClose
+	}
+	opts.inlineMaxWidth = p.InlineMaxWidth
+	opts.maxLineWidth = p.MaxLineWidth
+	opts.trailingSeparators = p.TrailingSeparators
+	return opts
+}
+
+// brackets returns the opening and closing
+// grouping characters for structs and maps.
+func (p *Printer) brackets() (opening, closing string) {
+	if p.groupDelimiters {
+		return string(groupOpen), string(groupClose)
+	}
+	o, c := parseBrackets(p.Brackets)
+	return string(o), string(c)
+}
+
+// groupOpen and groupClose are printed instead of the Brackets
+// of containers for indenting, so that tokens like Time(…)
+// are not indented if the Brackets are "()".
+// Control characters are escaped in quoted strings,
+// so they only occur as container delimiters.
+const (
+	groupOpen  = '\x0e'
+	groupClose = '\x0f'
+)
+
+// groupPrinter returns a copy of p that prints the brackets
+// of containers as groupOpen and groupClose if p.Brackets is set,
+// else p itself. The output has to be passed
+// through p.replaceGroupDelimiters after indenting.
+func (p *Printer) groupPrinter() *Printer {
+	if p.Brackets == "" {
+		return p
+	}
+	g := *p
+	g.groupDelimiters = true
+	// Cached strings are rendered with the Brackets
+	g.renderCache = nil
+	return &g
+}
+
+// replaceGroupDelimiters replaces groupOpen and groupClose
+// in output printed by p.groupPrinter with p.Brackets
+func (p *Printer) replaceGroupDelimiters(output []byte) []byte {
+	if p.Brackets == "" {
+		return output
+	}
+	opening, closing := p.brackets()
+	output = bytes.ReplaceAll(output, []byte{groupOpen}, []byte(opening))
+	return bytes.ReplaceAll(output, []byte{groupClose}, []byte(closing))
+}
+
+func (p *Printer) fprint(w io.Writer, v reflect.Value, s printState) {
+	if p.renderCache != nil && v.IsValid() && p.fprintCached(w, v, s) {
+		return
+	}
+	p.fprintValue(w, v, s)
+}
+
 //#nosec G104 -- We don't check for errors writing to w
-func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
+func (p *Printer) fprintValue(w io.Writer, v reflect.Value, s printState) {
+	if s.onTruncated != nil {
+		s.value = v
+	}
+	if format := p.valueFormatter(v); format != nil {
+		io.WriteString(w, format(v.Interface()))
+		return
+	}
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			fmt.Fprint(w, "nil")
+			io.WriteString(w, p.nilToken())
 			return
 		}
 		ptr := v.Pointer()
-		if ptrs.visit(ptr) {
-			fmt.Fprint(w, CircularRef)
+		if s.ptrs.visit(ptr, s.path) {
+			io.WriteString(w, p.circularRef(s.ptrs[ptr]))
 			return
 		}
-		defer delete(ptrs, ptr)
+		defer delete(s.ptrs, ptr)
+	}
+
+	aware, _ := v.Interface().(PrinterAware)
+	if aware == nil && v.CanAddr() {
+		aware, _ = v.Addr().Interface().(PrinterAware)
+	}
+	if aware != nil {
+		aware.PrettyPrintWith(p, w)
+		return
 	}
 
 	printer, _ := v.Interface().(Printable)
@@ -138,7 +993,22 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 		nullable, _ = v.Addr().Interface().(Nullable)
 	}
 	if nullable != nil && nullable.IsNull() {
-		fmt.Fprint(w, "null")
+		io.WriteString(w, p.nullToken())
+		return
+	}
+
+	if p.fprintJSONMarshaler(w, v, s) {
+		return
+	}
+
+	// Print database/sql Null types and other driver.Valuer
+	// implementations as their underlying value
+	if value, ok := driverValue(v); ok {
+		if value == nil {
+			io.WriteString(w, p.nullToken())
+			return
+		}
+		p.fprint(w, reflect.ValueOf(value), s)
 		return
 	}
 
@@ -150,8 +1020,14 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 		var inner string
 		if ctx.Err() != nil {
 			inner = "Err:" + Sprint(ctx.Err().Error())
+			if cause := contextCause(ctx); cause != nil && cause != ctx.Err() {
+				inner += ";Cause:" + Sprint(cause.Error())
+			}
+		} else if deadline, ok := ctx.Deadline(); ok {
+			inner = "Remaining:" + p.formatDuration(deadline.Sub(p.now()))
 		}
-		fmt.Fprintf(w, "Context{%s}", inner)
+		opening, closing := p.brackets()
+		io.WriteString(w, "Context"+opening+inner+closing)
 		return
 	}
 
@@ -159,15 +1035,69 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 		v = v.Elem()
 	}
 	t := v.Type()
+	if format := p.valueFormatter(v); format != nil {
+		io.WriteString(w, format(v.Interface()))
+		return
+	}
 
 	switch t {
 	case typeOfTime:
-		fmt.Fprintf(w, "Time(`%s`)", v.Interface())
+		io.WriteString(w, "Time(`"+p.formatTime(v.Interface().(time.Time))+"`)")
 		return
 	case typeOfDuration:
-		fmt.Fprintf(w, "Duration(`%s`)", v.Interface())
+		io.WriteString(w, p.formatDuration(time.Duration(v.Int())))
+		return
+	case typeOfCancel, typeOfCancelCause:
+		if v.IsNil() {
+			io.WriteString(w, p.nilToken())
+			return
+		}
+		io.WriteString(w, t.Name())
+		return
+	}
+	if isOpaqueType(t) {
+		io.WriteString(w, t.String())
 		return
 	}
+	if isBigNumberType(t) {
+		p.fprintBigNumber(w, v, s)
+		return
+	}
+	if isNetType(t) {
+		p.fprintNetValue(w, v, s)
+		return
+	}
+	if p.DetectUUIDs {
+		if str, ok := uuidString(v); ok {
+			name := t.Name()
+			if name == "" {
+				name = t.String()
+			}
+			io.WriteString(w, name+"("+str+")")
+			return
+		}
+	}
+	if bits, ok := p.bitmasks[t]; ok {
+		io.WriteString(w, sprintBitmask(v, bits))
+		return
+	}
+	if p.JSONValueMode && (t == typeOfJSONObject || t == typeOfJSONArray) {
+		p.fprintJSONValue(w, v, s)
+		return
+	}
+	if p.UseStringer && t.Kind() != reflect.Interface && implements(v, typeOfStringer) && !implements(v, typeOfError) {
+		io.WriteString(w, t.Name())
+		io.WriteString(w, "(")
+		p.fprintStringer(w, v, s)
+		io.WriteString(w, ")")
+		return
+	}
+
+	if p.ShowNamedTypeUnits && t.PkgPath() != "" && isNumericKind(t.Kind()) {
+		io.WriteString(w, t.Name())
+		io.WriteString(w, "(")
+		defer io.WriteString(w, ")")
+	}
 
 	switch t.Kind() {
 	case reflect.Ptr, reflect.Interface:
@@ -175,7 +1105,7 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 		if !v.IsNil() {
 			panic("expected nil")
 		}
-		fmt.Fprint(w, "nil")
+		io.WriteString(w, p.nilToken())
 
 	case reflect.String:
 		err, _ := v.Interface().(error)
@@ -183,60 +1113,140 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 			err, _ = v.Addr().Interface().(error)
 		}
 		if err != nil {
-			fmt.Fprintf(w, "error(%s)", quoteString(err, p.MaxErrorLength))
+			p.fprintError(w, err, s)
+			return
+		}
+		if v.Len() == 0 && p.EmptyStringToken != "" {
+			io.WriteString(w, p.EmptyStringToken)
+			return
+		}
+		if p.fprintJSONString(w, v.String(), s) {
 			return
 		}
-		fmt.Fprint(w, quoteString(v.Interface(), p.MaxStringLength))
+		q := p.quote(v.Interface(), s.limit(p.MaxStringLength), s)
+		if s.strs != nil && v.Len() >= p.InternStringsMinLength {
+			if ref := s.strs.ref(q); ref != "" {
+				q = ref
+			}
+		}
+		io.WriteString(w, q)
 
 	case reflect.Bool:
-		fmt.Fprint(w, v.Interface())
+		if t.PkgPath() == "" {
+			io.WriteString(w, strconv.FormatBool(v.Bool()))
+		} else {
+			fmt.Fprint(w, v.Interface())
+		}
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		fmt.Fprint(w, v.Interface())
+		if p.RunesAsChars && t.Kind() == reflect.Int32 && utf8.ValidRune(rune(v.Int())) && !implements(v, typeOfStringer) {
+			io.WriteString(w, strconv.QuoteRune(rune(v.Int())))
+			return
+		}
+		if t.PkgPath() == "" {
+			// Avoid fmt for predeclared types without methods
+			io.WriteString(w, strconv.FormatInt(v.Int(), 10))
+		} else {
+			fmt.Fprint(w, v.Interface())
+		}
+		if p.DetectUnixTimestamps && t.Kind() == reflect.Int64 {
+			fprintUnixTimestamp(w, v.Int())
+		}
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		fmt.Fprint(w, v.Interface())
+		if t.PkgPath() == "" {
+			io.WriteString(w, strconv.FormatUint(v.Uint(), 10))
+		} else {
+			fmt.Fprint(w, v.Interface())
+		}
+		if p.DetectUnixTimestamps && t.Kind() == reflect.Uint64 && v.Uint() <= math.MaxInt64 {
+			fprintUnixTimestamp(w, int64(v.Uint()))
+		}
 
 	case reflect.Uintptr:
 		fmt.Fprintf(w, "%#v", v.Interface())
 
 	case reflect.Float32, reflect.Float64:
+		if p.FloatSpecials && !implements(v, typeOfStringer) {
+			switch f := v.Float(); {
+			case math.IsNaN(f):
+				io.WriteString(w, `"NaN"`)
+				return
+			case math.IsInf(f, 1):
+				io.WriteString(w, `"+Inf"`)
+				return
+			case math.IsInf(f, -1):
+				io.WriteString(w, `"-Inf"`)
+				return
+			}
+		}
+		if p.FloatFormat.Format != 0 && !implements(v, typeOfStringer) {
+			io.WriteString(w, strconv.FormatFloat(v.Float(), p.FloatFormat.Format, p.FloatFormat.Precision, t.Bits()))
+			return
+		}
 		fmt.Fprint(w, v.Interface())
 
 	case reflect.Complex64, reflect.Complex128:
+		if p.FloatFormat.Format != 0 && !implements(v, typeOfStringer) {
+			io.WriteString(w, strconv.FormatComplex(v.Complex(), p.FloatFormat.Format, p.FloatFormat.Precision, t.Bits()))
+			return
+		}
 		fmt.Fprint(w, v.Interface())
 
 	case reflect.Array:
-		w.Write([]byte{'['})
-		for i := 0; i < v.Len(); i++ {
-			if i > 0 {
-				w.Write([]byte{','})
+		if p.HexByteArrays && t.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			for i := range b {
+				b[i] = byte(v.Index(i).Uint())
 			}
-			p.fprint(w, v.Index(i), ptrs)
+			h := hex.EncodeToString(b)
+			if maxLen := s.limit(p.MaxStringLength); maxLen > 0 && len(h) > maxLen {
+				s.truncated(len(h), maxLen)
+				h = h[:maxLen] + p.ellipsis()
+			}
+			name := t.Name()
+			if name == "" {
+				name = t.String()
+			}
+			fmt.Fprintf(w, "%s(%s)", name, h)
+			return
 		}
-		w.Write([]byte{']'})
+		if p.tooDeep(w, t, s) {
+			return
+		}
+		p.fprintList(w, v, v.Len(), s)
 
 	case reflect.Slice:
 		if v.IsNil() {
-			fmt.Fprint(w, "nil")
+			io.WriteString(w, p.nilToken())
 			return
 		}
 		ptr := v.Pointer()
-		if ptrs.visit(ptr) {
-			fmt.Fprint(w, CircularRef)
+		if s.ptrs.visit(ptr, s.path) {
+			io.WriteString(w, p.circularRef(s.ptrs[ptr]))
 			return
 		}
-		defer delete(ptrs, ptr)
+		defer delete(s.ptrs, ptr)
 		switch t.Elem() {
 		case typeOfByte:
 			b := v.Bytes()
 			if bytes.IndexByte(b, 0) == -1 && utf8.Valid(b) {
 				// Bytes are valid UTF-8 without zero, assume it's a string
-				fmt.Fprint(w, quoteString(b, p.MaxStringLength))
+				if len(b) == 0 && p.EmptyStringToken != "" {
+					io.WriteString(w, p.EmptyStringToken)
+					return
+				}
+				io.WriteString(w, p.quote(b, s.limit(p.MaxStringLength), s))
 				return
 			}
-			if len(b) > p.MaxSliceLength {
-				fmt.Fprintf(w, "[]byte{len(%d)}", len(b))
+			if maxLen := s.limit(p.MaxSliceLength); maxLen > 0 && len(b) > maxLen {
+				s.truncated(len(b), maxLen)
+				// Use the type name for named byte slices like sql.RawBytes
+				name := "[]byte"
+				if t.Name() != "" {
+					name = t.String()
+				}
+				fmt.Fprintf(w, "%s{len(%d)}", name, len(b))
 				return
 			}
 		case typeOfRune:
@@ -249,95 +1259,174 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 				}
 			}
 			if valid {
-				fmt.Fprint(w, quoteString(string(runes), p.MaxStringLength))
+				if len(runes) == 0 && p.EmptyStringToken != "" {
+					io.WriteString(w, p.EmptyStringToken)
+					return
+				}
+				io.WriteString(w, p.quote(string(runes), s.limit(p.MaxStringLength), s))
 				return
 			}
 		}
-		w.Write([]byte{'['})
-		for i := 0; i < v.Len(); i++ {
-			if i > 0 {
-				w.Write([]byte{','})
-			}
-			if p.MaxSliceLength > 0 && i >= p.MaxSliceLength {
-				fmt.Fprint(w, "…")
-				break
-			}
-			p.fprint(w, v.Index(i), ptrs)
+		if p.tooDeep(w, t, s) {
+			return
+		}
+		if p.SortSlices {
+			v = p.sortedSlice(v, s)
+		}
+		n := v.Len()
+		if maxLen := s.limit(p.MaxSliceLength); maxLen > 0 && n > maxLen {
+			s.truncated(n, maxLen)
+			n = maxLen
 		}
-		w.Write([]byte{']'})
+		p.fprintList(w, v, n, s)
 
 	case reflect.Map:
 		if v.IsNil() {
-			fmt.Fprint(w, "nil")
+			io.WriteString(w, p.nilToken())
 			return
 		}
 		ptr := v.Pointer()
-		if ptrs.visit(ptr) {
-			fmt.Fprint(w, CircularRef)
+		if s.ptrs.visit(ptr, s.path) {
+			io.WriteString(w, p.circularRef(s.ptrs[ptr]))
 			return
 		}
-		defer delete(ptrs, ptr)
-		fmt.Fprintf(w, "%s{", t.Name())
+		defer delete(s.ptrs, ptr)
+		if p.tooDeep(w, t, s) {
+			return
+		}
+		opening, closing := p.brackets()
+		io.WriteString(w, t.Name())
+		io.WriteString(w, opening)
 		mapKeys := v.MapKeys()
-		p.sortReflectValues(mapKeys, t.Key(), ptrs)
-		for i, key := range mapKeys {
-			if i > 0 {
-				w.Write([]byte{';'})
+		p.sortReflectValues(mapKeys, t.Key(), s)
+		if p.KeysOnly && s.depth >= p.KeysOnlyDepth && len(mapKeys) > 0 {
+			n := len(mapKeys)
+			if maxLen := s.limit(p.MaxSliceLength); maxLen > 0 && n > maxLen {
+				s.truncated(n, maxLen)
+				n = maxLen
+			}
+			io.WriteString(w, "keys:")
+			for i := 0; i < n; i++ {
+				if i > 0 {
+					io.WriteString(w, ",")
+				}
+				p.fprintMapKey(w, mapKeys[i], s.key(mapKeys[i]))
+			}
+			if n < len(mapKeys) {
+				io.WriteString(w, ","+p.ellipsis())
 			}
-			p.fprint(w, key, ptrs)
-			w.Write([]byte{':'})
-			p.fprint(w, v.MapIndex(key), ptrs)
+			io.WriteString(w, closing)
+			return
+		}
+		n := len(mapKeys)
+		if maxLen := s.limit(p.MaxMapLength); maxLen > 0 && n > maxLen {
+			s.truncated(n, maxLen)
+			n = maxLen
+		}
+		p.fprintElems(w, n, ";", s, func(w io.Writer, i int, s printState) {
+			s = s.key(mapKeys[i])
+			p.fprintMapKey(w, mapKeys[i], s)
+			io.WriteString(w, ":")
+			p.fprint(w, v.MapIndex(mapKeys[i]), s)
+		})
+		if n < len(mapKeys) {
+			io.WriteString(w, ";"+p.ellipsis()+"+"+strconv.Itoa(len(mapKeys)-n)+" more")
 		}
-		w.Write([]byte{'}'})
+		io.WriteString(w, closing)
 
 	case reflect.Struct:
-		hasExportedFields := false
-		for i := 0; i < t.NumField(); i++ {
-			if token.IsExported(t.Field(i).Name) {
-				hasExportedFields = true
-				break
+		fields := exportedFields(t)
+		hasExportedFields := len(fields) > 0
+		if p.IncludeUnexported {
+			fields = allFields(t)
+			if !v.CanAddr() {
+				// Unexported fields can only be read
+				// via the address of an addressable copy
+				c := reflect.New(t).Elem()
+				c.Set(v)
+				v = c
 			}
 		}
-		if !hasExportedFields {
-			err, _ := v.Interface().(error)
+		var err error
+		if !hasExportedFields || p.AppendStructErrors {
+			err, _ = v.Interface().(error)
 			if err == nil && v.CanAddr() {
 				err, _ = v.Addr().Interface().(error)
 			}
-			if err != nil {
-				fmt.Fprintf(w, "error(%s)", quoteString(err, p.MaxErrorLength))
-				return
-			}
+		}
+		if err != nil && !hasExportedFields {
+			p.fprintError(w, err, s)
+			return
+		}
+		if p.tooDeep(w, t, s) {
+			return
 		}
 
-		fmt.Fprintf(w, "%s{", t.Name())
-		first := true
-		for i := 0; i < t.NumField(); i++ {
-			f := t.Field(i)
-			if !token.IsExported(f.Name) {
+		opening, closing := p.brackets()
+		io.WriteString(w, t.Name())
+		io.WriteString(w, opening)
+		written, omitted := 0, 0
+		for _, f := range fields {
+			field := v.Field(f.index)
+			if f.unexported {
+				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem() //#nosec G103
+			}
+			if p.OmitZero && field.IsZero() {
 				continue
 			}
-			if first {
-				first = false
-			} else {
-				w.Write([]byte{';'})
+			if p.MaxStructFields > 0 && written >= p.MaxStructFields {
+				omitted++
+				continue
 			}
-			if !f.Anonymous {
-				fmt.Fprintf(w, "%s:", f.Name)
+			// Write separator and field label with a single call
+			switch {
+			case f.anonymous && written > 0:
+				io.WriteString(w, ";")
+			case !f.anonymous && written > 0:
+				io.WriteString(w, f.sepLabel)
+			case !f.anonymous:
+				io.WriteString(w, f.sepLabel[1:])
 			}
-			p.fprint(w, v.Field(i), ptrs)
+			written++
+			fs := s.nested()
+			if !f.anonymous {
+				fs = s.field(f.name)
+			}
+			fs.maxLen = f.maxLen
+			if p.InterfaceFieldsAsTypes && !f.expand && isNonEmptyInterface(field.Type()) && !field.IsNil() {
+				io.WriteString(w, field.Elem().Type().String())
+				continue
+			}
+			if f.stringer && p.fprintStringer(w, field, fs) {
+				continue
+			}
+			if f.set {
+				field = p.sortedSet(field, fs)
+			}
+			p.fprint(w, field, fs)
 		}
-		w.Write([]byte{'}'})
+		if omitted > 0 {
+			s.truncated(written+omitted, p.MaxStructFields)
+			io.WriteString(w, ";"+p.ellipsis()+"(+"+strconv.Itoa(omitted)+" fields)")
+		}
+		if err != nil {
+			if written > 0 {
+				io.WriteString(w, ";")
+			}
+			fmt.Fprintf(w, "err:%s", p.quote(err, p.MaxErrorLength, s))
+		}
+		io.WriteString(w, closing)
 
 	case reflect.Chan, reflect.Func:
 		if v.IsNil() {
-			fmt.Fprint(w, "nil")
+			io.WriteString(w, p.nilToken())
 			return
 		}
-		fmt.Fprint(w, t.String())
+		io.WriteString(w, t.String())
 
 	case reflect.UnsafePointer:
 		if v.IsNil() {
-			fmt.Fprint(w, "nil")
+			io.WriteString(w, p.nilToken())
 			return
 		}
 		fmt.Fprint(w, v.Interface())
@@ -347,11 +1436,229 @@ func (p *Printer) fprint(w io.Writer, v reflect.Value, ptrs visitedPtrs) {
 	}
 }
 
+// fprintStringer prints the quoted result of the String method
+// of v and returns true if v or its address implements fmt.Stringer
+// and is not a nil pointer or interface.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintStringer(w io.Writer, v reflect.Value, s printState) bool {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		return false
+	}
+	stringer, _ := v.Interface().(fmt.Stringer)
+	if stringer == nil && v.CanAddr() {
+		stringer, _ = v.Addr().Interface().(fmt.Stringer)
+	}
+	if stringer == nil {
+		return false
+	}
+	io.WriteString(w, p.quote(stringer.String(), s.limit(p.MaxStringLength), s))
+	return true
+}
+
+// fprintMapKey prints a map key.
+// Keys that are not printed as quoted strings, structs or arrays,
+// like custom types printed via fmt.Stringer,
+// are quoted if they contain separator or bracket characters
+// that would corrupt the format and the result of Indent.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintMapKey(w io.Writer, key reflect.Value, s printState) {
+	k := key
+	for (k.Kind() == reflect.Ptr || k.Kind() == reflect.Interface) && !k.IsNil() {
+		k = k.Elem()
+	}
+	switch k.Kind() {
+	case reflect.String, reflect.Struct, reflect.Array:
+		p.fprint(w, key, s)
+		return
+	}
+	if k.Type().PkgPath() == "" && key.Kind() != reflect.Interface {
+		// Predeclared types can't print separators
+		p.fprint(w, key, s)
+		return
+	}
+	var b strings.Builder
+	p.fprint(&b, key, s)
+	str := b.String()
+	opening, closing := parseBrackets(p.Brackets)
+	if strings.ContainsAny(str, ":;,`\"{}"+string(opening)+string(closing)) {
+		str = p.quote(str, 0, s)
+	}
+	io.WriteString(w, str)
+}
+
+// sortedSlice returns a sorted copy of the slice v
+// for the SortSlices option.
+func (p *Printer) sortedSlice(v reflect.Value, s printState) reflect.Value {
+	if v.Len() < 2 {
+		return v
+	}
+	elems := make([]reflect.Value, v.Len())
+	for i := range elems {
+		elems[i] = v.Index(i)
+	}
+	if p.SliceLess != nil {
+		sort.SliceStable(elems, func(i, j int) bool {
+			return p.SliceLess(elems[i].Interface(), elems[j].Interface())
+		})
+	} else {
+		p.sortReflectValues(elems, v.Type().Elem(), s)
+	}
+	sorted := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+	for i, elem := range elems {
+		sorted.Index(i).Set(elem)
+	}
+	return sorted
+}
+
+// sortedSet returns a sorted copy of the slice v
+// without duplicate elements for the "set" field tag.
+// Elements are duplicates if they are printed the same.
+// Other values are returned unchanged.
+func (p *Printer) sortedSet(v reflect.Value, s printState) reflect.Value {
+	if v.Kind() != reflect.Slice || v.Len() < 2 {
+		return v
+	}
+	sorted := p.sortedSlice(v, s)
+	set := reflect.MakeSlice(v.Type(), 0, sorted.Len())
+	var last string
+	for i := 0; i < sorted.Len(); i++ {
+		elem := sorted.Index(i)
+		str := p.sprintState(elem, s.nested())
+		if i > 0 && str == last {
+			continue
+		}
+		set = reflect.Append(set, elem)
+		last = str
+	}
+	return set
+}
+
+// tooDeep writes a placeholder for a value of the struct,
+// map, slice or array type t and returns true if the
+// nesting depth of s exceeds p.MaxDepth.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) tooDeep(w io.Writer, t reflect.Type, s printState) bool {
+	if p.MaxDepth <= 0 || s.depth < p.MaxDepth {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		io.WriteString(w, "["+p.ellipsis()+"]")
+	default:
+		opening, closing := p.brackets()
+		io.WriteString(w, t.Name()+opening+p.ellipsis()+closing)
+	}
+	return true
+}
+
+// fprintList prints the first n elements of the slice or array v
+// with an ellipsis as last element if n is less than its length.
+// If p.ShowIndexes is true and the output will be indented,
+// then the elements are labeled with their index like []{[0]:a;[1]:b}
+// so that every element is printed on its own line.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintList(w io.Writer, v reflect.Value, n int, s printState) {
+	if p.ShowIndexes && s.indented && n > 0 {
+		opening, closing := p.brackets()
+		io.WriteString(w, "[]")
+		io.WriteString(w, opening)
+		p.fprintElems(w, n, ";", s, func(w io.Writer, i int, s printState) {
+			io.WriteString(w, "["+strconv.Itoa(i)+"]:")
+			p.fprint(w, v.Index(i), s.index(i))
+		})
+		if n < v.Len() {
+			io.WriteString(w, ";"+p.ellipsis())
+		}
+		io.WriteString(w, closing)
+		return
+	}
+	io.WriteString(w, "[")
+	p.fprintElems(w, n, ",", s, func(w io.Writer, i int, s printState) {
+		p.fprint(w, v.Index(i), s.index(i))
+	})
+	if n < v.Len() {
+		io.WriteString(w, ","+p.ellipsis())
+	}
+	io.WriteString(w, "]")
+}
+
+// fprintElems prints n elements of a slice, array or map
+// separated by sep using the passed elem function
+// that gets passed the state of the parent value.
+// If p.Parallel is true, the elements of a top-level value
+// are rendered concurrently into separate buffers
+// that are written in order to w.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintElems(w io.Writer, n int, sep string, s printState, elem func(w io.Writer, i int, s printState)) {
+	if !p.Parallel || s.depth > 0 || n < 2 || s.strs != nil || s.truncs != nil || s.onTruncated != nil {
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				io.WriteString(w, sep)
+			}
+			elem(w, i, s)
+		}
+		return
+	}
+
+	var (
+		bufs = make([]bytes.Buffer, n)
+		sem  = make(chan struct{}, runtime.GOMAXPROCS(0))
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < n; i++ {
+		// Every goroutine needs its own copy of the visited pointers
+		es := s
+		es.ptrs = make(visitedPtrs, len(s.ptrs))
+		for ptr, path := range s.ptrs {
+			es.ptrs[ptr] = path
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, es printState) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			elem(&bufs[i], i, es)
+		}(i, es)
+	}
+	wg.Wait()
+	for i := range bufs {
+		if i > 0 {
+			io.WriteString(w, sep)
+		}
+		w.Write(bufs[i].Bytes())
+	}
+}
+
+const (
+	minUnixTimestamp = 946684800  // 2000-01-01T00:00:00Z
+	maxUnixTimestamp = 4102444800 // 2100-01-01T00:00:00Z
+)
+
+// fprintUnixTimestamp writes the UTC time of ts in parentheses
+// if it is plausible as Unix seconds or milliseconds.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func fprintUnixTimestamp(w io.Writer, ts int64) {
+	switch {
+	case ts >= minUnixTimestamp && ts < maxUnixTimestamp:
+		fmt.Fprintf(w, "(%s)", time.Unix(ts, 0).UTC().Format(time.RFC3339))
+	case ts >= minUnixTimestamp*1000 && ts < maxUnixTimestamp*1000:
+		fmt.Fprintf(w, "(%s)", time.UnixMilli(ts).UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	}
+}
+
 // sortReflectValues sorts a slice of reflected values.
 // All values must be of the same type passed as valType.
 // The < operator is used if the value's type supports it,
 // else the pretty printed string representations are compared.
-func (p *Printer) sortReflectValues(vals []reflect.Value, valType reflect.Type, ptrs visitedPtrs) {
+func (p *Printer) sortReflectValues(vals []reflect.Value, valType reflect.Type, s printState) {
 	if len(vals) < 2 {
 		return
 	}
@@ -389,22 +1696,143 @@ func (p *Printer) sortReflectValues(vals []reflect.Value, valType reflect.Type,
 			return
 		}
 	}
+	// Don't intern strings that are only printed for comparison
+	s.strs = nil
 	sort.Slice(vals, func(i, j int) bool {
+		if less, ok := lessByKind(vals[i], vals[j]); ok {
+			return less
+		}
 		var ip, jp strings.Builder
-		p.fprint(&ip, vals[i], ptrs)
-		p.fprint(&jp, vals[j], ptrs)
+		p.fprint(&ip, vals[i], s)
+		p.fprint(&jp, vals[j], s)
 		return ip.String() < jp.String()
 	})
 }
 
-func quoteString(s any, maxLen int) string {
-	q := fmt.Sprintf("%#q", s)
+// lessByKind compares the values of a and b
+// unwrapped from interfaces if both are numbers,
+// strings or booleans, so that for example keys
+// of named integer types in a map[any]T are
+// sorted numerically instead of by their printed form.
+// Nil interfaces are sorted first.
+// Returns false for ok if a and b can't be compared by kind.
+func lessByKind(a, b reflect.Value) (less, ok bool) {
+	for a.Kind() == reflect.Interface && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Interface && !b.IsNil() {
+		b = b.Elem()
+	}
+	aNil := a.Kind() == reflect.Interface
+	bNil := b.Kind() == reflect.Interface
+	if aNil || bNil {
+		return aNil && !bNil, true
+	}
+	switch {
+	case isIntKind(a.Kind()) && isIntKind(b.Kind()):
+		return a.Int() < b.Int(), true
+	case isUintKind(a.Kind()) && isUintKind(b.Kind()):
+		return a.Uint() < b.Uint(), true
+	case isIntKind(a.Kind()) && isUintKind(b.Kind()):
+		return a.Int() < 0 || uint64(a.Int()) < b.Uint(), true
+	case isUintKind(a.Kind()) && isIntKind(b.Kind()):
+		return b.Int() >= 0 && a.Uint() < uint64(b.Int()), true
+	case isRealKind(a.Kind()) && isRealKind(b.Kind()):
+		return toFloat(a) < toFloat(b), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() < b.String(), true
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return !a.Bool() && b.Bool(), true
+	}
+	return false, false
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+// isRealKind returns if k is an integer or float kind
+func isRealKind(k reflect.Kind) bool {
+	return isIntKind(k) || isUintKind(k) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// toFloat returns the integer or float number v as float64
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isIntKind(v.Kind()):
+		return float64(v.Int())
+	case isUintKind(v.Kind()):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// quote quotes s with quoteString after sanitizing it
+// if p.SanitizeForLogs is true and collapsing whitespace
+// if p.CollapseWhitespace is true and records a truncation.
+func (p *Printer) quote(s any, maxLen int, st printState) string {
+	str := toString(s)
+	if p.SanitizeForLogs {
+		str = sanitizeString(str)
+	}
+	if p.CollapseWhitespace {
+		str = collapseWhitespace(str, p.times())
+	}
+	str = p.redact(str)
+	if !utf8.ValidString(str) {
+		switch p.InvalidUTF8 {
+		case InvalidUTF8Replace:
+			str = strings.ToValidUTF8(str, p.replacementChar())
+		case InvalidUTF8Hex:
+			h := hex.EncodeToString([]byte(str))
+			if maxLen > 0 && len(h) > maxLen {
+				st.truncated(len(h), maxLen)
+				h = h[:maxLen] + p.ellipsis()
+			}
+			return "hex(" + h + ")"
+		}
+	}
+	q, truncated := quoteString(str, maxLen)
+	if truncated {
+		st.truncated(len(str), maxLen)
+		if ellipsis := p.ellipsis(); ellipsis != "…" {
+			// The ellipsis is followed by the closing quote
+			q = q[:len(q)-1-len("…")] + ellipsis + q[len(q)-1:]
+		}
+	}
+	return q
+}
+
+// toString returns the string of a string, byte slice, error,
+// fmt.Stringer or the fmt.Sprint result for other types.
+func toString(s any) string {
+	switch x := s.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func quoteString(s string, maxLen int) (q string, truncated bool) {
+	q = fmt.Sprintf("%#q", s)
 	if maxLen > 0 && len(q)-2 > maxLen {
 		// Compare byte length as first approximation,
 		// but then count runes to trim at avalid rune byte position
 		for i := range q {
 			if i > maxLen {
 				q = q[:i] + "…" + q[len(q)-1:]
+				truncated = true
 				break
 			}
 		}
@@ -413,5 +1841,33 @@ func quoteString(s any, maxLen int) string {
 	if q[0] == '"' && q[len(q)-1] == '"' {
 		q = "`" + q[1:len(q)-1] + "`"
 	}
-	return q
+	return q, truncated
+}
+
+// singleLineWriter escapes newlines and carriage returns
+// before writing to the wrapped io.Writer
+type singleLineWriter struct {
+	w io.Writer
+}
+
+func (s singleLineWriter) Write(b []byte) (int, error) {
+	if bytes.IndexByte(b, '\n') == -1 && bytes.IndexByte(b, '\r') == -1 {
+		return s.w.Write(b)
+	}
+	escaped := make([]byte, 0, len(b)+8)
+	for _, c := range b {
+		switch c {
+		case '\n':
+			escaped = append(escaped, '\\', 'n')
+		case '\r':
+			escaped = append(escaped, '\\', 'r')
+		default:
+			escaped = append(escaped, c)
+		}
+	}
+	_, err := s.w.Write(escaped)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
 }
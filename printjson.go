@@ -3,6 +3,7 @@ package pretty
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -14,6 +15,40 @@ import (
 // to indent JSON lines.
 // A byte slice as input will be marshalled as json.RawMessage.
 func PrintAsJSON(input any, indent ...string) {
+	data, err := marshalIndentJSON(input, indent)
+	if err != nil {
+		_, _ = fmt.Println(err)
+		return
+	}
+	_, _ = fmt.Println(string(data))
+}
+
+// SprintAsJSON marshalles input as indented JSON
+// and returns the result as string
+// using the same indent rules as PrintAsJSON.
+// A byte slice as input will be marshalled as json.RawMessage.
+func SprintAsJSON(input any, indent ...string) (string, error) {
+	data, err := marshalIndentJSON(input, indent)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// FprintAsJSON marshalles input as indented JSON
+// and writes the result to w
+// using the same indent rules as PrintAsJSON.
+// A byte slice as input will be marshalled as json.RawMessage.
+func FprintAsJSON(w io.Writer, input any, indent ...string) error {
+	data, err := marshalIndentJSON(input, indent)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func marshalIndentJSON(input any, indent []string) ([]byte, error) {
 	var indentStr string
 	if len(indent) == 0 {
 		indentStr = "  "
@@ -25,8 +60,7 @@ func PrintAsJSON(input any, indent ...string) {
 	}
 	data, err := json.MarshalIndent(input, "", indentStr)
 	if err != nil {
-		_, _ = fmt.Println(fmt.Errorf("%w from input: %#v", err, input))
-		return
+		return nil, fmt.Errorf("%w from input: %#v", err, input)
 	}
-	_, _ = fmt.Println(string(data))
+	return data, nil
 }
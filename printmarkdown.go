@@ -0,0 +1,207 @@
+package pretty
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SprintMarkdown returns value as Markdown
+// using the default Printer, see Printer.FprintMarkdown.
+func SprintMarkdown(value any) string {
+	return Default().SprintMarkdown(value)
+}
+
+// SprintMarkdown returns value as Markdown,
+// see Printer.FprintMarkdown.
+func (p *Printer) SprintMarkdown(value any) string {
+	var b strings.Builder
+	p.FprintMarkdown(&b, value)
+	return b.String()
+}
+
+// FprintMarkdown writes value as Markdown ending with a newline to w
+// suitable for pasting into issues and pull request descriptions.
+// Structs, maps, slices and arrays are written as nested bullet lists
+// with struct fields and map keys in bold and all other values
+// as inline code of their pretty printed form.
+// The value is traversed like for printing,
+// so the limits like MaxSliceLength are applied
+// with truncated elements noted like "…+3 more".
+// Values that are not written as list,
+// like strings or empty structs, are written as fenced code block.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) FprintMarkdown(w io.Writer, value any) {
+	s := p.newPrintState()
+	lines := p.markdownLines(reflect.ValueOf(value), s)
+	if lines == nil {
+		io.WriteString(w, "```\n"+p.sprintState(reflect.ValueOf(value), s)+"\n```\n")
+		return
+	}
+	for _, line := range lines {
+		io.WriteString(w, line)
+		io.WriteString(w, "\n")
+	}
+}
+
+// markdownLines returns the bullet list lines of v
+// or nil if v is not written as list
+func (p *Printer) markdownLines(v reflect.Value, s printState) (lines []string) {
+	if !v.IsValid() {
+		return nil
+	}
+	for !p.isStructuredLeaf(v) && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, s.path) {
+				return nil
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		v = v.Elem()
+	}
+	if p.isStructuredLeaf(v) {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		written, omitted := 0, 0
+		for _, f := range exportedFields(v.Type()) {
+			field := v.Field(f.index)
+			if p.OmitZero && field.IsZero() {
+				continue
+			}
+			if p.MaxStructFields > 0 && written >= p.MaxStructFields {
+				omitted++
+				continue
+			}
+			written++
+			if f.anonymous {
+				if embedded := p.markdownLines(field, s.nested()); embedded != nil {
+					// Inline the fields of embedded structs
+					lines = append(lines, embedded...)
+					continue
+				}
+			}
+			lines = p.appendMarkdownItem(lines, "**"+markdownEscape(f.name)+"**: ", field, s.field(f.name), 0)
+		}
+		if omitted > 0 {
+			lines = append(lines, "- "+p.ellipsis()+"(+"+strconv.Itoa(omitted)+" fields)")
+		}
+		return lines
+
+	case reflect.Map:
+		if v.IsNil() || v.Len() == 0 {
+			return nil
+		}
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, s.path) {
+			return nil
+		}
+		defer delete(s.ptrs, ptr)
+		keys := v.MapKeys()
+		p.sortReflectValues(keys, v.Type().Key(), s)
+		n := len(keys)
+		if p.MaxMapLength > 0 && n > p.MaxMapLength {
+			n = p.MaxMapLength
+		}
+		for _, key := range keys[:n] {
+			var keyStr string
+			if key.Kind() == reflect.String {
+				keyStr = p.toMapString(key.String())
+			} else {
+				keyStr = p.sprintState(key, s)
+			}
+			lines = p.appendMarkdownItem(lines, "**"+markdownEscape(keyStr)+"**: ", v.MapIndex(key), s.key(key), 0)
+		}
+		if n < len(keys) {
+			lines = append(lines, "- "+p.ellipsis()+"+"+strconv.Itoa(len(keys)-n)+" more")
+		}
+		return lines
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				return nil
+			}
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, s.path) {
+				return nil
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		if v.Len() == 0 {
+			return nil
+		}
+		n := v.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength {
+			n = p.MaxSliceLength
+		}
+		for i := 0; i < n; i++ {
+			lines = p.appendMarkdownItem(lines, "", v.Index(i), s.index(i), i)
+		}
+		if n < v.Len() {
+			lines = append(lines, "- "+p.ellipsis()+"+"+strconv.Itoa(v.Len()-n)+" more")
+		}
+		return lines
+	}
+	return nil
+}
+
+// appendMarkdownItem appends the list item
+// with the label and value to lines.
+// Unlabeled slice elements with a nested list
+// are labeled with their index.
+func (p *Printer) appendMarkdownItem(lines []string, label string, value reflect.Value, s printState, index int) []string {
+	nested := p.markdownLines(value, s)
+	if nested == nil {
+		return append(lines, "- "+label+markdownCode(p.sprintState(value, s)))
+	}
+	if label == "" {
+		label = "**\\[" + strconv.Itoa(index) + "\\]**:"
+	}
+	lines = append(lines, "- "+strings.TrimSuffix(label, " "))
+	for _, line := range nested {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+// markdownCode returns str as inline code span
+// delimited by more backticks than contained in str.
+func markdownCode(str string) string {
+	longest, run := 0, 0
+	for _, r := range str {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	delim := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(str, "`") || strings.HasSuffix(str, "`") {
+		return delim + " " + str + " " + delim
+	}
+	return delim + str + delim
+}
+
+// markdownEscape escapes the Markdown syntax characters in str
+func markdownEscape(str string) string {
+	var b strings.Builder
+	for _, r := range str {
+		if strings.ContainsRune("\\`*_{}[]<>()#+-.!|~", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,418 @@
+package pretty
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SprintAsTOML returns value as TOML document
+// using the default Printer, see Printer.FprintAsTOML.
+func SprintAsTOML(value any) (string, error) {
+	return Default().SprintAsTOML(value)
+}
+
+// SprintAsTOML returns value as TOML document,
+// see Printer.FprintAsTOML.
+func (p *Printer) SprintAsTOML(value any) (string, error) {
+	var b strings.Builder
+	if err := p.FprintAsTOML(&b, value); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// FprintAsTOML writes value as TOML document to w,
+// for example to dump the effective configuration
+// of services that are configured with TOML files.
+// The value must be a struct or map that becomes the root table.
+// Nested structs and maps become tables and slices
+// of structs or maps become arrays of tables.
+// Exported struct fields and maps sorted by key
+// are traversed like for printing with the limits
+// like MaxSliceLength and MaxStringLength applied
+// and truncated elements noted as comments like "# …+3 more".
+// Nil values are omitted because TOML has no null value.
+// Values that are printed as a whole like implementations
+// of Printable, errors or registered formatters
+// are written as TOML strings of their pretty printed form.
+func (p *Printer) FprintAsTOML(w io.Writer, value any) error {
+	v := reflect.ValueOf(value)
+	if p.tomlKind(v, p.newPrintState()) != tomlTable {
+		return fmt.Errorf("can't print %T as TOML table", value)
+	}
+	var b strings.Builder
+	p.writeTOMLTable(&b, "", v, p.newPrintState())
+	_, err := io.WriteString(w, strings.TrimPrefix(b.String(), "\n"))
+	return err
+}
+
+type tomlKindType int
+
+const (
+	tomlNull tomlKindType = iota
+	tomlValue
+	tomlTable
+	tomlArrayOfTables
+)
+
+// tomlEntry is a key value pair of a TOML table
+type tomlEntry struct {
+	key   string
+	value reflect.Value
+	s     printState
+}
+
+// tomlKind returns how v is written as TOML
+func (p *Printer) tomlKind(v reflect.Value, s printState) tomlKindType {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return tomlNull
+		}
+		if p.isStructuredLeaf(v) {
+			break
+		}
+		if v.Kind() == reflect.Ptr {
+			if _, visited := s.ptrs[v.Pointer()]; visited {
+				// Circular references are written as value
+				return tomlValue
+			}
+		}
+		v = v.Elem()
+	}
+	switch {
+	case !v.IsValid():
+		return tomlNull
+	case p.isStructuredLeaf(v):
+		return tomlValue
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return tomlTable
+	case reflect.Map:
+		if v.IsNil() {
+			return tomlNull
+		}
+		return tomlTable
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return tomlNull
+		}
+		if v.Len() == 0 {
+			return tomlValue
+		}
+		for i := 0; i < v.Len(); i++ {
+			if p.tomlKind(v.Index(i), s) != tomlTable {
+				return tomlValue
+			}
+		}
+		return tomlArrayOfTables
+	}
+	return tomlValue
+}
+
+// tomlEntries returns the key value pairs of the struct
+// or map v and a comment if entries were truncated.
+// The caller has to dereference v.
+func (p *Printer) tomlEntries(v reflect.Value, s printState) (entries []tomlEntry, comment string) {
+	switch v.Kind() {
+	case reflect.Struct:
+		written, omitted := 0, 0
+		for _, f := range exportedFields(v.Type()) {
+			field := v.Field(f.index)
+			if p.OmitZero && field.IsZero() {
+				continue
+			}
+			if p.MaxStructFields > 0 && written >= p.MaxStructFields {
+				omitted++
+				continue
+			}
+			written++
+			if f.anonymous {
+				embedded := field
+				for embedded.Kind() == reflect.Ptr && !embedded.IsNil() {
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct && !p.isStructuredLeaf(embedded) {
+					// Inline the fields of embedded structs
+					embeddedEntries, _ := p.tomlEntries(embedded, s.nested())
+					entries = append(entries, embeddedEntries...)
+					continue
+				}
+			}
+			entries = append(entries, tomlEntry{key: f.name, value: field, s: s.field(f.name)})
+		}
+		if omitted > 0 {
+			comment = "# " + p.ellipsis() + "(+" + strconv.Itoa(omitted) + " fields)"
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		p.sortReflectValues(keys, v.Type().Key(), s)
+		n := len(keys)
+		if p.MaxMapLength > 0 && n > p.MaxMapLength {
+			n = p.MaxMapLength
+			comment = "# " + p.ellipsis() + "+" + strconv.Itoa(len(keys)-n) + " more"
+		}
+		for _, key := range keys[:n] {
+			var keyStr string
+			if key.Kind() == reflect.String {
+				keyStr = key.String()
+			} else {
+				keyStr = p.sprintState(key, s)
+			}
+			entries = append(entries, tomlEntry{key: keyStr, value: v.MapIndex(key), s: s.key(key)})
+		}
+	}
+	return entries, comment
+}
+
+// writeTOMLTable writes the entries of the struct or map v
+// with the sub-tables prefixed with the table path.
+func (p *Printer) writeTOMLTable(b *strings.Builder, path string, v reflect.Value, s printState) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			s.ptrs.visit(ptr, s.path)
+			defer delete(s.ptrs, ptr)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Map {
+		ptr := v.Pointer()
+		s.ptrs.visit(ptr, s.path)
+		defer delete(s.ptrs, ptr)
+	}
+	entries, comment := p.tomlEntries(v, s)
+	var tables, arrays []tomlEntry
+	for _, e := range entries {
+		switch p.tomlKind(e.value, e.s) {
+		case tomlValue:
+			b.WriteString(p.tomlKey(e.key) + " = " + p.tomlValue(e.value, e.s))
+			if more := p.tomlTruncatedElems(e.value); more > 0 {
+				b.WriteString(" # " + p.ellipsis() + "+" + strconv.Itoa(more) + " more")
+			}
+			b.WriteString("\n")
+		case tomlTable:
+			tables = append(tables, e)
+		case tomlArrayOfTables:
+			arrays = append(arrays, e)
+		}
+	}
+	if comment != "" {
+		b.WriteString(comment + "\n")
+	}
+	for _, e := range tables {
+		tablePath := p.joinTOMLPath(path, e.key)
+		b.WriteString("\n[" + tablePath + "]\n")
+		p.writeTOMLTable(b, tablePath, e.value, e.s)
+	}
+	for _, e := range arrays {
+		tablePath := p.joinTOMLPath(path, e.key)
+		elems := e.value
+		for elems.Kind() == reflect.Ptr || elems.Kind() == reflect.Interface {
+			elems = elems.Elem()
+		}
+		n := elems.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength {
+			n = p.MaxSliceLength
+		}
+		for i := 0; i < n; i++ {
+			b.WriteString("\n[[" + tablePath + "]]\n")
+			p.writeTOMLTable(b, tablePath, elems.Index(i), e.s.index(i))
+		}
+		if n < elems.Len() {
+			b.WriteString("# " + p.ellipsis() + "+" + strconv.Itoa(elems.Len()-n) + " more\n")
+		}
+	}
+}
+
+// tomlTruncatedElems returns the number of elements
+// of the slice v that are not written because of MaxSliceLength.
+func (p *Printer) tomlTruncatedElems(v reflect.Value) int {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice || p.isStructuredLeaf(v) || p.MaxSliceLength <= 0 || v.Len() <= p.MaxSliceLength {
+		return 0
+	}
+	return v.Len() - p.MaxSliceLength
+}
+
+// tomlValue returns v as inline TOML value
+func (p *Printer) tomlValue(v reflect.Value, s printState) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return `""`
+		}
+		if p.isStructuredLeaf(v) {
+			break
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, s.path) {
+				return p.tomlString(p.circularRef(s.ptrs[ptr]))
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		v = v.Elem()
+	}
+	if p.isStructuredLeaf(v) {
+		return p.tomlScalar(v, s)
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		entries, _ := p.tomlEntries(v, s)
+		items := make([]string, 0, len(entries))
+		for _, e := range entries {
+			if p.tomlKind(e.value, e.s) != tomlNull {
+				items = append(items, p.tomlKey(e.key)+" = "+p.tomlValue(e.value, e.s))
+			}
+		}
+		if len(items) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+
+	case reflect.Slice, reflect.Array:
+		n := v.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength {
+			n = p.MaxSliceLength
+		}
+		items := make([]string, n)
+		for i := range items {
+			items[i] = p.tomlValue(v.Index(i), s.index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return p.tomlString(p.sprintState(v, s))
+}
+
+// tomlScalar returns v as TOML scalar
+func (p *Printer) tomlScalar(v reflect.Value, s printState) string {
+	t := v.Type()
+	if p.hasCustomFormat(v) {
+		return p.tomlString(p.sprintState(v, s))
+	}
+	switch t {
+	case typeOfTime:
+		tm := v.Interface().(time.Time)
+		if p.TimeLocation != nil {
+			tm = tm.In(p.TimeLocation)
+		}
+		return tm.Format(time.RFC3339Nano)
+	case typeOfDuration:
+		return p.tomlString(time.Duration(v.Int()).String())
+	}
+	if implements(v, typeOfError) && !(v.Kind() == reflect.Ptr && v.IsNil()) {
+		err, _ := v.Interface().(error)
+		if err == nil {
+			err, _ = v.Addr().Interface().(error)
+		}
+		return p.tomlString(p.toMapString(err.Error()))
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !implements(v, typeOfStringer) {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !implements(v, typeOfStringer) {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !implements(v, typeOfStringer) {
+			return tomlFloat(v.Float(), t.Bits())
+		}
+	case reflect.String:
+		return p.tomlString(p.toMapString(v.String()))
+	case reflect.Slice:
+		if t.Elem() == typeOfByte && utf8.Valid(v.Bytes()) {
+			return p.tomlString(p.toMapString(string(v.Bytes())))
+		}
+		if t.Elem() == typeOfRune {
+			return p.tomlString(p.toMapString(string(v.Interface().([]rune))))
+		}
+	}
+	return p.tomlString(p.sprintState(v, s))
+}
+
+// tomlFloat returns f as TOML float
+// which always has a decimal point or exponent
+func tomlFloat(f float64, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	str := strconv.FormatFloat(f, 'g', -1, bits)
+	if !strings.ContainsAny(str, ".e") {
+		str += ".0"
+	}
+	return str
+}
+
+// tomlKey returns key as bare TOML key
+// if possible, else as quoted key.
+func (p *Printer) tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return p.tomlString(key)
+		}
+	}
+	return key
+}
+
+func (p *Printer) joinTOMLPath(path, key string) string {
+	if path == "" {
+		return p.tomlKey(key)
+	}
+	return path + "." + p.tomlKey(key)
+}
+
+// tomlString returns str as TOML basic string
+// with invalid UTF-8 replaced like for InvalidUTF8Replace
+func (p *Printer) tomlString(str string) string {
+	var b strings.Builder
+	b.Grow(len(str) + 2)
+	b.WriteByte('"')
+	for _, r := range strings.ToValidUTF8(str, p.replacementChar()) {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
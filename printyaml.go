@@ -0,0 +1,321 @@
+package pretty
+
+import (
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// PrintAsYAML prints value as indented YAML
+// using the default Printer to os.Stdout,
+// see Printer.FprintAsYAML.
+func PrintAsYAML(value any) {
+	Default().FprintAsYAML(os.Stdout, value)
+}
+
+// SprintAsYAML returns value as indented YAML
+// using the default Printer, see Printer.FprintAsYAML.
+func SprintAsYAML(value any) string {
+	return Default().SprintAsYAML(value)
+}
+
+// SprintAsYAML returns value as indented YAML,
+// see Printer.FprintAsYAML.
+func (p *Printer) SprintAsYAML(value any) string {
+	var b strings.Builder
+	p.FprintAsYAML(&b, value)
+	return b.String()
+}
+
+// FprintAsYAML writes value as indented YAML block
+// ending with a newline to w, which is more readable
+// for deeply nested configurations than the single line format.
+// The value is traversed like for printing:
+// Exported struct fields become mapping keys,
+// maps are sorted by key, the limits like MaxSliceLength
+// and MaxStringLength are applied with truncated elements
+// noted as comments like "# …+3 more",
+// and circular references are printed as CircularRefToken.
+// Values that are printed as a whole like implementations
+// of Printable, errors or registered formatters
+// are written as YAML strings of their pretty printed form.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) FprintAsYAML(w io.Writer, value any) {
+	lines, _ := p.yamlLines(reflect.ValueOf(value), p.newPrintState())
+	for _, line := range lines {
+		io.WriteString(w, line)
+		io.WriteString(w, "\n")
+	}
+}
+
+// yamlLines returns the YAML lines of v
+// and true if they are a block mapping or sequence
+// that has to start on a new line after a mapping key.
+func (p *Printer) yamlLines(v reflect.Value, s printState) (lines []string, block bool) {
+	if !v.IsValid() {
+		return []string{"null"}, false
+	}
+	for !p.isStructuredLeaf(v) && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return []string{"null"}, false
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, s.path) {
+				return []string{yamlString(p.circularRef(s.ptrs[ptr]))}, false
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		v = v.Elem()
+	}
+	if p.isStructuredLeaf(v) {
+		return []string{p.yamlScalar(v, s)}, false
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := exportedFields(v.Type())
+		written, omitted := 0, 0
+		for _, f := range fields {
+			field := v.Field(f.index)
+			if p.OmitZero && field.IsZero() {
+				continue
+			}
+			if p.MaxStructFields > 0 && written >= p.MaxStructFields {
+				omitted++
+				continue
+			}
+			written++
+			if f.anonymous {
+				embedded, embeddedBlock := p.yamlLines(field, s.nested())
+				if embeddedBlock && !strings.HasPrefix(embedded[0], "- ") {
+					// Inline the fields of embedded structs
+					lines = append(lines, embedded...)
+					continue
+				}
+			}
+			lines = p.appendYAMLEntry(lines, yamlString(f.name), field, s.field(f.name))
+		}
+		if omitted > 0 {
+			lines = append(lines, "# "+p.ellipsis()+"(+"+strconv.Itoa(omitted)+" fields)")
+		}
+		if len(lines) == 0 {
+			return []string{"{}"}, false
+		}
+		return lines, true
+
+	case reflect.Map:
+		if v.IsNil() {
+			return []string{"null"}, false
+		}
+		if v.Len() == 0 {
+			return []string{"{}"}, false
+		}
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, s.path) {
+			return []string{yamlString(p.circularRef(s.ptrs[ptr]))}, false
+		}
+		defer delete(s.ptrs, ptr)
+		keys := v.MapKeys()
+		p.sortReflectValues(keys, v.Type().Key(), s)
+		n := len(keys)
+		if p.MaxMapLength > 0 && n > p.MaxMapLength {
+			n = p.MaxMapLength
+		}
+		for _, key := range keys[:n] {
+			var keyStr string
+			if key.Kind() == reflect.String {
+				keyStr = key.String()
+			} else {
+				keyStr = p.sprintState(key, s)
+			}
+			lines = p.appendYAMLEntry(lines, yamlString(keyStr), v.MapIndex(key), s.key(key))
+		}
+		if n < len(keys) {
+			lines = append(lines, "# "+p.ellipsis()+"+"+strconv.Itoa(len(keys)-n)+" more")
+		}
+		return lines, true
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				return []string{"null"}, false
+			}
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, s.path) {
+				return []string{yamlString(p.circularRef(s.ptrs[ptr]))}, false
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		if v.Len() == 0 {
+			return []string{"[]"}, false
+		}
+		n := v.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength {
+			n = p.MaxSliceLength
+		}
+		for i := 0; i < n; i++ {
+			elem, _ := p.yamlLines(v.Index(i), s.index(i))
+			lines = append(lines, "- "+elem[0])
+			for _, line := range elem[1:] {
+				lines = append(lines, "  "+line)
+			}
+		}
+		if n < v.Len() {
+			lines = append(lines, "# "+p.ellipsis()+"+"+strconv.Itoa(v.Len()-n)+" more")
+		}
+		return lines, true
+	}
+	return []string{yamlString(p.sprintState(v, s))}, false
+}
+
+// appendYAMLEntry appends the mapping entry key: value to lines
+func (p *Printer) appendYAMLEntry(lines []string, key string, value reflect.Value, s printState) []string {
+	valueLines, block := p.yamlLines(value, s)
+	if !block {
+		return append(lines, key+": "+valueLines[0])
+	}
+	lines = append(lines, key+":")
+	for _, line := range valueLines {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+// isStructuredLeaf returns if v is written as single scalar
+// by the structured output formats like YAML
+func (p *Printer) isStructuredLeaf(v reflect.Value) bool {
+	if !p.isLeafValue(v) {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		// Print empty structs as empty mapping
+		return v.NumField() > 0 || p.hasCustomFormat(v) || implements(v, typeOfError)
+	case reflect.Map, reflect.Slice, reflect.Array:
+		// Print empty containers as empty mapping or sequence,
+		// byte and rune slices are strings
+		return v.Len() > 0 || p.hasCustomFormat(v)
+	}
+	return true
+}
+
+// hasCustomFormat returns if v is printed by a registered
+// formatter or bitmask or a Printable or PrinterAware implementation
+func (p *Printer) hasCustomFormat(v reflect.Value) bool {
+	return p.valueFormatter(v) != nil || p.bitmasks[v.Type()] != nil || implements(v, typeOfPrintable) || implements(v, typeOfPrinterAware)
+}
+
+// yamlScalar returns v as YAML scalar
+func (p *Printer) yamlScalar(v reflect.Value, s printState) string {
+	t := v.Type()
+	if p.hasCustomFormat(v) {
+		return yamlString(p.sprintState(v, s))
+	}
+	switch t {
+	case typeOfTime:
+		return yamlString(p.formatTime(v.Interface().(time.Time)))
+	case typeOfDuration:
+		return yamlString(time.Duration(v.Int()).String())
+	}
+	if implements(v, typeOfError) && !(v.Kind() == reflect.Ptr && v.IsNil()) {
+		err, _ := v.Interface().(error)
+		if err == nil {
+			err, _ = v.Addr().Interface().(error)
+		}
+		return yamlString(p.toMapString(err.Error()))
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return "null"
+		}
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !implements(v, typeOfStringer) {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !implements(v, typeOfStringer) {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !implements(v, typeOfStringer) {
+			return yamlFloat(v.Float(), p.FloatFormat, t.Bits())
+		}
+	case reflect.String:
+		return yamlString(p.toMapString(v.String()))
+	case reflect.Slice:
+		if t.Elem() == typeOfByte && utf8.Valid(v.Bytes()) {
+			return yamlString(p.toMapString(string(v.Bytes())))
+		}
+		if t.Elem() == typeOfRune {
+			return yamlString(p.toMapString(string(v.Interface().([]rune))))
+		}
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			return "null"
+		}
+	}
+	return yamlString(p.sprintState(v, s))
+}
+
+// yamlFloat returns f as YAML float
+func yamlFloat(f float64, format FloatFormat, bits int) string {
+	switch {
+	case math.IsNaN(f):
+		return ".nan"
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	}
+	if format.Format == 0 {
+		format = FloatShortest
+	}
+	return strconv.FormatFloat(f, format.Format, format.Precision, bits)
+}
+
+// yamlString returns str as plain YAML scalar
+// if that is unambiguous, else double quoted.
+func yamlString(str string) string {
+	if isPlainYAML(str) {
+		return str
+	}
+	return strconv.Quote(str)
+}
+
+// isPlainYAML returns if str can be written as
+// plain YAML scalar without being read as
+// another type or containing YAML syntax.
+func isPlainYAML(str string) bool {
+	if str == "" || str != strings.TrimSpace(str) {
+		return false
+	}
+	switch strings.ToLower(str) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n", ".inf", "-.inf", ".nan":
+		return false
+	}
+	if _, err := strconv.ParseFloat(str, 64); err == nil {
+		return false
+	}
+	if strings.ContainsAny(str[:1], "-?:,[]{}#&*!|>'\"%@`0123456789.+") {
+		return false
+	}
+	if strings.Contains(str, ": ") || strings.Contains(str, " #") {
+		return false
+	}
+	for _, r := range str {
+		if !strconv.IsPrint(r) || strings.ContainsRune(",[]{}", r) {
+			return false
+		}
+	}
+	return true
+}
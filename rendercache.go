@@ -0,0 +1,149 @@
+package pretty
+
+import (
+	"container/list"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DefaultRenderCacheSize is the maximum number of rendered values
+// cached by a Printer with registered immutable types
+// if Printer.RenderCacheSize is zero.
+const DefaultRenderCacheSize = 1024
+
+// RegisterImmutable registers types whose values always
+// render identically, like *time.Location, *regexp.Regexp
+// or enum-like constants, so that their rendered strings
+// are cached in a least recently used cache of the Printer
+// with up to RenderCacheSize entries.
+// Values are cached by their type and pointer
+// for pointer types, or by their type and value
+// for booleans, numbers and strings.
+// Values of other kinds are not cached.
+// The nesting depth and indentation are part of the cache key,
+// and values are not cached while printing with
+// InternStringsMinLength, CircularRefPath,
+// TruncationSidecar or OnTruncatedValue
+// because their output depends on the printed path.
+// Pointed to values must not be modified after printing.
+// Not safe for concurrent use with printing.
+func (p *Printer) RegisterImmutable(types ...reflect.Type) {
+	if p.immutables == nil {
+		p.immutables = make(map[reflect.Type]bool)
+	}
+	for _, t := range types {
+		p.immutables[t] = true
+	}
+	p.renderCache = new(renderCache)
+}
+
+// renderCacheKeyOf returns the cache key for v
+// and false if values of the kind of v are not cached.
+func renderCacheKeyOf(v reflect.Value) (renderCacheKey, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return renderCacheKey{t: v.Type(), value: v.Pointer()}, true
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String:
+		if v.CanInterface() {
+			return renderCacheKey{t: v.Type(), value: v.Interface()}, true
+		}
+	}
+	return renderCacheKey{}, false
+}
+
+// fprintCached prints v using the render cache
+// and returns false if v is not cacheable.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) fprintCached(w io.Writer, v reflect.Value, s printState) bool {
+	if s.maxLen != 0 || s.trackPath || s.strs != nil || !p.immutables[v.Type()] {
+		return false
+	}
+	key, ok := renderCacheKeyOf(v)
+	if !ok {
+		return false
+	}
+	key.depth = s.depth
+	key.indented = s.indented
+	str, ok := p.renderCache.get(key)
+	if !ok {
+		// Render without the visited pointers of the parents
+		// so that the result doesn't depend on where v is printed
+		s.ptrs = make(visitedPtrs)
+		var b strings.Builder
+		p.fprintValue(&b, v, s)
+		str = b.String()
+		size := p.RenderCacheSize
+		if size == 0 {
+			size = DefaultRenderCacheSize
+		}
+		p.renderCache.add(key, str, size)
+	}
+	io.WriteString(w, str)
+	return true
+}
+
+type renderCacheKey struct {
+	t        reflect.Type
+	value    any
+	depth    int
+	indented bool
+}
+
+type renderCacheEntry struct {
+	key renderCacheKey
+	str string
+}
+
+// renderCache is a least recently used cache
+// of rendered strings that is safe for concurrent use.
+type renderCache struct {
+	mtx     sync.Mutex
+	entries map[renderCacheKey]*list.Element
+	order   list.List // front is the most recently used
+}
+
+func (c *renderCache) get(key renderCacheKey) (string, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*renderCacheEntry).str, true
+}
+
+func (c *renderCache) add(key renderCacheKey, str string, size int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*renderCacheEntry).str = str
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[renderCacheKey]*list.Element)
+	}
+	c.entries[key] = c.order.PushFront(&renderCacheEntry{key: key, str: str})
+	for c.order.Len() > size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*renderCacheEntry).key)
+	}
+}
+
+func (c *renderCache) len() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,137 @@
+package pretty
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sanitizeString removes ANSI escape sequences,
+// control characters except tab, newline and carriage return,
+// and Unicode bidirectional formatting characters from s.
+func sanitizeString(s string) string {
+	if !needsSanitizing(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == '\x1b' {
+			i += escapeSequenceLen(s[i:])
+			continue
+		}
+		if !isUnsafeRune(r) {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func needsSanitizing(s string) bool {
+	for _, r := range s {
+		if r == '\x1b' || isUnsafeRune(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnsafeRune returns true for C0 and C1 control characters
+// except tab, newline and carriage return,
+// and for Unicode bidirectional formatting characters
+// that can be used to visually reorder log lines.
+func isUnsafeRune(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return false
+	case r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0):
+		return true
+	case r >= 0x202a && r <= 0x202e, r >= 0x2066 && r <= 0x2069:
+		return true
+	}
+	return false
+}
+
+// escapeSequenceLen returns the byte length of the
+// escape sequence at the beginning of s starting with ESC.
+func escapeSequenceLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		// CSI sequence: parameter and intermediate bytes
+		// terminated by a final byte in the range 0x40–0x7e
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', 'X', '^', '_':
+		// OSC, DCS, SOS, PM, APC strings
+		// terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	// Two byte escape sequence
+	return 2
+}
+
+// collapseWhitespace replaces every run of at least two
+// whitespace runes in str with a marker of the run length
+// surrounded by single spaces like " (×12) "
+// where times replaces the ×.
+func collapseWhitespace(str, times string) string {
+	var (
+		b     strings.Builder
+		run   = 0
+		start = 0
+	)
+	flush := func(end int) {
+		switch run {
+		case 0:
+		case 1:
+			b.WriteString(str[start:end])
+		default:
+			b.WriteString(" (" + times)
+			b.WriteString(strconv.Itoa(run))
+			b.WriteString(") ")
+		}
+		run = 0
+	}
+	for i := 0; i < len(str); {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if unicode.IsSpace(r) {
+			if run == 0 {
+				start = i
+			}
+			run++
+		} else {
+			flush(i)
+			// Copy invalid UTF-8 unchanged
+			b.WriteString(str[i : i+size])
+		}
+		i += size
+	}
+	flush(len(str))
+	return b.String()
+}
+
+// redact replaces all matches of p.RedactPatterns in s with Redacted
+func (p *Printer) redact(s string) string {
+	for _, re := range p.RedactPatterns {
+		s = re.ReplaceAllLiteralString(s, Redacted)
+	}
+	return s
+}
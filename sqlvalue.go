@@ -0,0 +1,29 @@
+package pretty
+
+import (
+	"database/sql/driver"
+	"reflect"
+)
+
+var typeOfValuer = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// driverValue returns the result of the Value method
+// if v or its address implements driver.Valuer,
+// like sql.NullString or sql.NullInt64.
+// Returns false if v is not a driver.Valuer
+// or the Value method returned an error.
+func driverValue(v reflect.Value) (value driver.Value, ok bool) {
+	valuer, _ := v.Interface().(driver.Valuer)
+	if valuer == nil && v.CanAddr() {
+		valuer, _ = v.Addr().Interface().(driver.Valuer)
+	}
+	if valuer == nil {
+		return nil, false
+	}
+	value, err := valuer.Value()
+	if err != nil || value != nil && reflect.TypeOf(value) == v.Type() {
+		// Don't recurse endlessly for values returning themselves
+		return nil, false
+	}
+	return value, true
+}
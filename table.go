@@ -0,0 +1,195 @@
+package pretty
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FprintMapTable pretty prints a map with struct keys
+// as aligned text table to w.
+// The exported fields of the key struct are used as columns
+// followed by a Value column and rows are sorted by key.
+// Other values are printed like with Fprintln.
+func (p *Printer) FprintMapTable(w io.Writer, m any) {
+	v := reflect.ValueOf(m)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.Struct {
+		p.Fprintln(w, m)
+		return
+	}
+	keyFields := exportedFields(v.Type().Key())
+	header := make([]string, 0, len(keyFields)+1)
+	for _, f := range keyFields {
+		header = append(header, f.name)
+	}
+	header = append(header, "Value")
+
+	s := printState{ptrs: make(visitedPtrs)}
+	keys := v.MapKeys()
+	p.sortReflectValues(keys, v.Type().Key(), s)
+	rows := make([][]string, len(keys))
+	for i, key := range keys {
+		row := make([]string, 0, len(header))
+		for _, f := range keyFields {
+			row = append(row, p.sprintState(key.Field(f.index), s))
+		}
+		rows[i] = append(row, p.sprintState(v.MapIndex(key), s))
+	}
+	writeTable(w, header, rows)
+}
+
+// SprintMapTable pretty prints a map with struct keys
+// as aligned text table to a string.
+func (p *Printer) SprintMapTable(m any) string {
+	var b strings.Builder
+	p.FprintMapTable(&b, m)
+	return b.String()
+}
+
+// FprintTable pretty prints a slice or array of structs,
+// pointers to structs or maps with string keys
+// as aligned text table to w with one row per element.
+// The exported fields of the struct type are used as columns
+// up to MaxStructFields, the columns of maps are
+// the sorted union of their keys.
+// Cells contain the pretty printed field or map values
+// with the limits like MaxStringLength applied,
+// missing map keys are empty cells and nil pointers
+// are printed in the first column.
+// Rows are truncated to MaxSliceLength with
+// a last line like "…+3 more" for the truncated rows.
+// Other values are printed like with Fprintln.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func (p *Printer) FprintTable(w io.Writer, slice any) {
+	v := reflect.ValueOf(slice)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		p.Fprintln(w, slice)
+		return
+	}
+	elemType := v.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	isMap := elemType.Kind() == reflect.Map && elemType.Key().Kind() == reflect.String
+	if elemType.Kind() != reflect.Struct && !isMap {
+		p.Fprintln(w, slice)
+		return
+	}
+
+	n := v.Len()
+	if p.MaxSliceLength > 0 && n > p.MaxSliceLength {
+		n = p.MaxSliceLength
+	}
+	var (
+		s      = printState{ptrs: make(visitedPtrs)}
+		header []string
+		fields []structField
+	)
+	if isMap {
+		keys := make(map[string]bool)
+		for i := 0; i < n; i++ {
+			elem := reflect.Indirect(v.Index(i))
+			if !elem.IsValid() {
+				continue
+			}
+			iter := elem.MapRange()
+			for iter.Next() {
+				if key := iter.Key().String(); !keys[key] {
+					keys[key] = true
+					header = append(header, key)
+				}
+			}
+		}
+		sort.Strings(header)
+	} else {
+		fields = exportedFields(elemType)
+		if p.MaxStructFields > 0 && len(fields) > p.MaxStructFields {
+			fields = fields[:p.MaxStructFields]
+		}
+		for _, f := range fields {
+			header = append(header, f.name)
+		}
+	}
+	if len(header) == 0 {
+		p.Fprintln(w, slice)
+		return
+	}
+
+	rows := make([][]string, n)
+	for i := range rows {
+		row := make([]string, len(header))
+		elem := v.Index(i)
+		if isPtr && elem.IsNil() {
+			row[0] = p.nilToken()
+			rows[i] = row
+			continue
+		}
+		elem = reflect.Indirect(elem)
+		for col, name := range header {
+			if isMap {
+				if value := elem.MapIndex(reflect.ValueOf(name).Convert(elemType.Key())); value.IsValid() {
+					row[col] = p.sprintState(value, s)
+				}
+			} else {
+				row[col] = p.sprintState(elem.Field(fields[col].index), s)
+			}
+		}
+		rows[i] = row
+	}
+	writeTable(w, header, rows)
+	if n < v.Len() {
+		io.WriteString(w, p.ellipsis()+"+"+strconv.Itoa(v.Len()-n)+" more\n")
+	}
+}
+
+// SprintTable pretty prints a slice of structs or maps
+// as aligned text table to a string, see FprintTable.
+func (p *Printer) SprintTable(slice any) string {
+	var b strings.Builder
+	p.FprintTable(&b, slice)
+	return b.String()
+}
+
+func (p *Printer) sprintState(v reflect.Value, s printState) string {
+	var b strings.Builder
+	p.fprint(&b, v, s)
+	return b.String()
+}
+
+// writeTable writes header and rows as left aligned columns
+// separated by two spaces with every row ending in a newline.
+//
+// #nosec G104 -- We don't check for errors writing to w
+func writeTable(w io.Writer, header []string, rows [][]string) {
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for col, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[col] {
+				widths[col] = n
+			}
+		}
+	}
+	var b strings.Builder
+	for _, row := range append([][]string{header}, rows...) {
+		b.Reset()
+		for col, cell := range row {
+			b.WriteString(cell)
+			if col < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[col]-utf8.RuneCountInString(cell)+2))
+			}
+		}
+		b.WriteByte('\n')
+		io.WriteString(w, b.String())
+	}
+}
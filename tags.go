@@ -0,0 +1,49 @@
+package pretty
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseFieldTag parses the comma separated options
+// of a `pretty:"..."` struct field tag into f
+// and returns true if the field should be omitted.
+// Supported options:
+//
+//	"-"     omits the field
+//	name=N  prints the field with the name N
+//	max=N   caps the printed length of a string, slice or map field at N
+//	string  prints the result of the String method of a fmt.Stringer field
+//	expand  prints interface fields completely with Printer.InterfaceFieldsAsTypes
+//	set     prints a slice field that is semantically a set sorted and without duplicates
+func parseFieldTag(tag reflect.StructTag, f *structField) (omit bool) {
+	options, ok := tag.Lookup("pretty")
+	if !ok {
+		return false
+	}
+	if strings.TrimSpace(options) == "-" {
+		return true
+	}
+	for _, option := range strings.Split(options, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch name {
+		case "name":
+			if value != "" {
+				f.name = value
+				f.sepLabel = ";" + value + ":"
+			}
+		case "string":
+			f.stringer = true
+		case "max":
+			if max, err := strconv.Atoi(value); err == nil && max > 0 {
+				f.maxLen = max
+			}
+		case "expand":
+			f.expand = true
+		case "set":
+			f.set = true
+		}
+	}
+	return false
+}
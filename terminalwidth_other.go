@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package pretty
+
+import "os"
+
+// terminalWidth returns 0 because querying the
+// terminal size is not implemented for this platform.
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,27 @@
+//go:build linux || darwin
+
+package pretty
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the number of columns
+// of the terminal f or 0 if f is not a terminal.
+func terminalWidth(f *os.File) int {
+	var size struct {
+		rows, cols, xPixels, yPixels uint16
+	}
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		f.Fd(),
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&size)), //#nosec G103
+	)
+	if errno != 0 {
+		return 0
+	}
+	return int(size.cols)
+}
@@ -0,0 +1,200 @@
+package pretty
+
+import (
+	"reflect"
+	"unicode/utf8"
+)
+
+// ToMap converts value to a generic map for structured logging
+// using the same traversal rules as the printing of value.
+// Structs are converted to maps with their exported field names
+// as keys with the fields of embedded structs merged into them.
+// Maps get the pretty printed keys of non string types
+// and slices and arrays are converted to []any.
+// Booleans and numbers are used as is, nil and null values
+// as nil and other values like strings, errors, times
+// or implementations of Printable or PrinterAware as their pretty printed string
+// without quotes.
+// The configured limits are applied with truncated strings
+// and slices ending with an ellipsis.
+// If value is not a struct or map, then it's returned
+// under the key "value".
+func (p *Printer) ToMap(value any) map[string]any {
+	v := p.toMapValue(reflect.ValueOf(value), printState{ptrs: make(visitedPtrs)})
+	if m, ok := v.(map[string]any); ok {
+		return m
+	}
+	return map[string]any{"value": v}
+}
+
+func (p *Printer) toMapValue(v reflect.Value, s printState) any {
+	if p.isLeafValue(v) {
+		return p.toMapLeaf(v, s)
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, "") {
+				return p.circularRefToken()
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		v = v.Elem()
+		if p.isLeafValue(v) {
+			return p.toMapLeaf(v, s)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		m := make(map[string]any)
+		fields := exportedFields(v.Type())
+		if p.MaxStructFields > 0 && len(fields) > p.MaxStructFields {
+			fields = fields[:p.MaxStructFields]
+		}
+		for _, f := range fields {
+			field := p.toMapValue(v.Field(f.index), s.nested())
+			if embedded, ok := field.(map[string]any); ok && f.anonymous {
+				for key, val := range embedded {
+					m[key] = val
+				}
+				continue
+			}
+			m[f.name] = field
+		}
+		return m
+
+	case reflect.Map:
+		ptr := v.Pointer()
+		if s.ptrs.visit(ptr, "") {
+			return p.circularRefToken()
+		}
+		defer delete(s.ptrs, ptr)
+		keys := v.MapKeys()
+		p.sortReflectValues(keys, v.Type().Key(), s)
+		if p.MaxMapLength > 0 && len(keys) > p.MaxMapLength {
+			keys = keys[:p.MaxMapLength]
+		}
+		m := make(map[string]any, len(keys))
+		for _, key := range keys {
+			k := p.sprintState(key, s)
+			if key.Kind() == reflect.String {
+				k = key.String()
+			}
+			m[k] = p.toMapValue(v.MapIndex(key), s.nested())
+		}
+		return m
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			ptr := v.Pointer()
+			if s.ptrs.visit(ptr, "") {
+				return p.circularRefToken()
+			}
+			defer delete(s.ptrs, ptr)
+		}
+		n := v.Len()
+		if p.MaxSliceLength > 0 && n > p.MaxSliceLength && v.Kind() == reflect.Slice {
+			n = p.MaxSliceLength
+		}
+		elems := make([]any, n, n+1)
+		for i := range elems {
+			elems[i] = p.toMapValue(v.Index(i), s.nested())
+		}
+		if n < v.Len() {
+			elems = append(elems, p.ellipsis())
+		}
+		return elems
+	}
+	return p.toMapLeaf(v, s)
+}
+
+// toMapLeaf returns the value of a leaf
+// as defined by isLeafValue for ToMap.
+func (p *Printer) toMapLeaf(v reflect.Value, s printState) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if isNullValue(v) {
+		return nil
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if value, ok := driverValue(v); ok {
+		if value == nil {
+			return nil
+		}
+		return p.toMapLeaf(reflect.ValueOf(value), s)
+	}
+	t := v.Type()
+	if implements(v, typeOfPrintable) || implements(v, typeOfPrinterAware) || implements(v, typeOfContext) || p.bitmasks[t] != nil {
+		return p.sprintState(v, s)
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if t == typeOfDuration || implements(v, typeOfStringer) {
+			return p.sprintState(v, s)
+		}
+		return v.Interface()
+
+	case reflect.String:
+		if implements(v, typeOfError) {
+			return p.sprintState(v, s)
+		}
+		return p.toMapString(v.String())
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		switch t.Elem() {
+		case typeOfByte:
+			if utf8.Valid(v.Bytes()) {
+				return p.toMapString(string(v.Bytes()))
+			}
+		case typeOfRune:
+			return p.toMapString(string(v.Interface().([]rune)))
+		}
+		if v.Len() == 0 {
+			return []any{}
+		}
+
+	case reflect.Array:
+		if v.Len() == 0 {
+			return []any{}
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Len() == 0 {
+			return map[string]any{}
+		}
+	}
+	return p.sprintState(v, s)
+}
+
+// toMapString returns str sanitized and truncated
+// to MaxStringLength with an ellipsis for ToMap.
+func (p *Printer) toMapString(str string) string {
+	if p.SanitizeForLogs {
+		str = sanitizeString(str)
+	}
+	str = p.redact(str)
+	if p.MaxStringLength > 0 && len(str) > p.MaxStringLength {
+		n := p.MaxStringLength
+		for n > 0 && !utf8.RuneStart(str[n]) {
+			n--
+		}
+		str = str[:n] + p.ellipsis()
+	}
+	return str
+}
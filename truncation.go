@@ -0,0 +1,95 @@
+package pretty
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// TotalLengthMarker ends output that was cut
+// because of Printer.MaxTotalLength.
+const TotalLengthMarker = "…(truncated)"
+
+// Truncation describes a value that was truncated
+// because of a limit like Printer.MaxSliceLength.
+type Truncation struct {
+	// Path of the value like "Sub.Items[0].Name",
+	// empty for the printed value itself
+	Path string `json:"path"`
+	// Length of the original value
+	Length int `json:"length"`
+	// Limit that was applied
+	Limit int `json:"limit"`
+}
+
+// truncated records a truncation of the value at s.path
+// and calls s.onTruncated with it.
+func (s printState) truncated(length, limit int) {
+	if s.truncs != nil {
+		*s.truncs = append(*s.truncs, Truncation{Path: s.path, Length: length, Limit: limit})
+	}
+	if s.onTruncated != nil {
+		s.onTruncated(s.path, s.value)
+	}
+}
+
+func (p *Printer) emitTruncationSidecar(s printState) {
+	if s.truncs == nil || len(*s.truncs) == 0 || p.TruncationSidecar == nil {
+		return
+	}
+	sidecar, err := json.Marshal(*s.truncs)
+	if err != nil {
+		return
+	}
+	p.TruncationSidecar(sidecar)
+}
+
+// totalLengthWriter buffers up to max+1 bytes
+// and discards everything after that.
+type totalLengthWriter struct {
+	buf bytes.Buffer
+	max int
+	// marker ends cut output, see TotalLengthMarker
+	marker string
+}
+
+func (t *totalLengthWriter) Write(b []byte) (int, error) {
+	if free := t.max + 1 - t.buf.Len(); free > 0 {
+		if len(b) > free {
+			t.buf.Write(b[:free])
+		} else {
+			t.buf.Write(b)
+		}
+	}
+	return len(b), nil
+}
+
+func (t *totalLengthWriter) WriteString(s string) (int, error) {
+	if free := t.max + 1 - t.buf.Len(); free > 0 {
+		if len(s) > free {
+			t.buf.WriteString(s[:free])
+		} else {
+			t.buf.WriteString(s)
+		}
+	}
+	return len(s), nil
+}
+
+// bytes returns the written bytes if they are within max,
+// else they are cut to end with t.marker.
+// If max is too small for the marker, then the bytes are cut without it.
+func (t *totalLengthWriter) bytes() []byte {
+	b := t.buf.Bytes()
+	if len(b) <= t.max {
+		return b
+	}
+	marker := t.marker
+	if len(marker) > t.max {
+		marker = ""
+	}
+	n := t.max - len(marker)
+	for n > 0 && !utf8.RuneStart(b[n]) {
+		n--
+	}
+	return append(b[:n:n], marker...)
+}
@@ -0,0 +1,693 @@
+package pretty
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Unmarshal parses data in the compact or indented format
+// of the default Printer into the value pointed to by dst,
+// see Printer.Unmarshal.
+func Unmarshal(data []byte, dst any) error {
+	return Default().Unmarshal(data, dst)
+}
+
+// Unmarshal parses data in the compact or indented format
+// of the Printer into the value pointed to by dst,
+// for example to use pretty printed values as human friendly
+// test fixtures instead of JSON.
+//
+// Parsing is best-effort: Only exported struct fields can be set,
+// and the output of Printable implementations
+// or other custom formats can't be parsed.
+// Quoted strings containing backslashes are unescaped
+// if they are valid Go escape sequences.
+// Type names like in Name{…} or Name(…) are ignored.
+// Values implementing encoding.TextUnmarshaler
+// are set from the unquoted string.
+// Interface values get map[string]any for structs and maps,
+// []any for slices and arrays, int or float64 for numbers,
+// and bool, string or nil for the other values.
+//
+// Truncated values can't be restored: Elements, entries and fields
+// marked as truncated with the ellipsis of p.MetaRunes are skipped
+// and strings truncated by MaxStringLength are parsed as printed
+// including the ellipsis without returning an error,
+// so fixtures have to be printed by a Printer without limits.
+func (p *Printer) Unmarshal(data []byte, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("can't unmarshal into non pointer or nil %T", dst)
+	}
+	ps := &parser{data: string(data), ellipsis: p.ellipsis()}
+	n, err := ps.parseValue()
+	if err != nil {
+		return err
+	}
+	ps.skipSpace()
+	if ps.pos < len(ps.data) {
+		return ps.errorf("unexpected %q after value", ps.data[ps.pos:])
+	}
+	return n.assign(v.Elem(), "")
+}
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	quotedNode
+	compositeNode
+	listNode
+	callNode
+)
+
+// node is a parsed value of the pretty format
+type node struct {
+	kind    nodeKind
+	name    string // token for scalarNode, type name for compositeNode and callNode
+	str     string // unquoted string of quotedNode
+	entries []nodeEntry
+	elems   []*node
+	arg     *node
+	// truncation is true for an ellipsis
+	// that was printed instead of truncated elements,
+	// like … or …+3 more or …(+3 fields)
+	truncation bool
+}
+
+// nodeEntry is a struct field or map element,
+// key is nil for embedded struct fields
+type nodeEntry struct {
+	key   *node
+	value *node
+}
+
+// isTruncation returns if n is an ellipsis
+// that was printed instead of truncated elements
+func (n *node) isTruncation() bool {
+	return n.truncation
+}
+
+// isNil returns if n is the nil or null token
+func (n *node) isNil() bool {
+	return n.kind == scalarNode && (n.name == "nil" || n.name == "null")
+}
+
+// text returns the unquoted string of n
+// or the string of a single argument call like Name(`str`)
+func (n *node) text() (string, bool) {
+	switch n.kind {
+	case quotedNode:
+		return n.str, true
+	case scalarNode:
+		return n.name, true
+	case callNode:
+		if n.arg != nil {
+			return n.arg.text()
+		}
+	}
+	return "", false
+}
+
+// number returns the numeric token of n.
+// A call like 1594728574(2020-07-14T12:09:34Z)
+// returns the number before the parentheses
+// and a call like UserID(123) the number in them.
+func (n *node) number() (string, bool) {
+	switch n.kind {
+	case scalarNode:
+		return n.name, true
+	case callNode:
+		if n.name != "" && (n.name[0] == '-' || n.name[0] >= '0' && n.name[0] <= '9') {
+			return n.name, true
+		}
+		if n.arg != nil {
+			return n.arg.number()
+		}
+	}
+	return "", false
+}
+
+var typeOfTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// timeLayouts are tried to parse time.Time values
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+}
+
+// assign sets v to the value of n
+func (n *node) assign(v reflect.Value, path string) error {
+	if n.isNil() {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	t := v.Type()
+	switch t {
+	case typeOfTime:
+		str, ok := n.text()
+		if !ok {
+			return n.errorf(path, t)
+		}
+		if i := strings.Index(str, " m="); i >= 0 {
+			str = str[:i]
+		}
+		for _, layout := range timeLayouts {
+			if tm, err := time.Parse(layout, str); err == nil {
+				v.Set(reflect.ValueOf(tm))
+				return nil
+			}
+		}
+		return n.errorf(path, t)
+
+	case typeOfDuration:
+		if n.kind == callNode && n.arg != nil && n.arg.kind == quotedNode {
+			d, err := time.ParseDuration(n.arg.str)
+			if err != nil {
+				return fmt.Errorf("can't unmarshal %s: %w", pathOrValue(path), err)
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+	}
+	if v.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(typeOfTextUnmarshaler) {
+		if str, ok := n.text(); ok {
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return n.assign(v.Elem(), path)
+
+	case reflect.Interface:
+		switch {
+		case t.NumMethod() == 0:
+			v.Set(reflect.ValueOf(n.generic()))
+		case t == typeOfError:
+			str, ok := n.text()
+			if !ok {
+				return n.errorf(path, t)
+			}
+			v.Set(reflect.ValueOf(errors.New(str)))
+		default:
+			return n.errorf(path, t)
+		}
+		return nil
+
+	case reflect.String:
+		str, ok := n.text()
+		if !ok {
+			return n.errorf(path, t)
+		}
+		v.SetString(str)
+		return nil
+
+	case reflect.Bool:
+		str, _ := n.text()
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return n.errorf(path, t)
+		}
+		v.SetBool(b)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		str, _ := n.number()
+		i, err := strconv.ParseInt(str, 10, t.Bits())
+		if err != nil {
+			return n.errorf(path, t)
+		}
+		v.SetInt(i)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		str, _ := n.number()
+		u, err := strconv.ParseUint(str, 0, t.Bits())
+		if err != nil {
+			return n.errorf(path, t)
+		}
+		v.SetUint(u)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		str, _ := n.number()
+		f, err := strconv.ParseFloat(str, t.Bits())
+		if err != nil {
+			return n.errorf(path, t)
+		}
+		v.SetFloat(f)
+		return nil
+
+	case reflect.Complex64, reflect.Complex128:
+		str, _ := n.number()
+		c, err := strconv.ParseComplex(str, t.Bits())
+		if err != nil {
+			return n.errorf(path, t)
+		}
+		v.SetComplex(c)
+		return nil
+
+	case reflect.Slice:
+		if n.kind == quotedNode && t.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(n.str))
+			return nil
+		}
+		if n.kind == quotedNode && t.Elem().Kind() == reflect.Int32 {
+			v.Set(reflect.ValueOf([]rune(n.str)).Convert(t))
+			return nil
+		}
+		if n.kind != listNode {
+			return n.errorf(path, t)
+		}
+		elems := n.listElems()
+		slice := reflect.MakeSlice(t, len(elems), len(elems))
+		for i, elem := range elems {
+			if err := elem.assign(slice.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+
+	case reflect.Array:
+		if n.kind != listNode {
+			return n.errorf(path, t)
+		}
+		elems := n.listElems()
+		if len(elems) > v.Len() {
+			return fmt.Errorf("can't unmarshal %d elements into %s of %s", len(elems), pathOrValue(path), t)
+		}
+		for i, elem := range elems {
+			if err := elem.assign(v.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		if n.kind != compositeNode {
+			return n.errorf(path, t)
+		}
+		m := reflect.MakeMapWithSize(t, len(n.entries))
+		for _, e := range n.entries {
+			if e.value.isTruncation() {
+				continue
+			}
+			if e.key == nil {
+				return fmt.Errorf("can't unmarshal map element without key into %s", pathOrValue(path))
+			}
+			key := reflect.New(t.Key()).Elem()
+			if err := e.key.assign(key, path); err != nil {
+				return err
+			}
+			keyStr, _ := e.key.text()
+			val := reflect.New(t.Elem()).Elem()
+			if err := e.value.assign(val, joinPath(path, keyStr)); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, val)
+		}
+		v.Set(m)
+		return nil
+
+	case reflect.Struct:
+		if n.kind != compositeNode {
+			return n.errorf(path, t)
+		}
+		fields := exportedFields(t)
+		embedded := 0
+		for _, e := range n.entries {
+			if e.value.isTruncation() {
+				continue
+			}
+			if e.key == nil {
+				// Embedded structs are printed without field name
+				// in the order of the struct fields
+				for embedded < len(fields) && !fields[embedded].anonymous {
+					embedded++
+				}
+				if embedded == len(fields) {
+					return fmt.Errorf("can't unmarshal value without field name into %s of %s", pathOrValue(path), t)
+				}
+				if err := e.value.assign(v.Field(fields[embedded].index), path); err != nil {
+					return err
+				}
+				embedded++
+				continue
+			}
+			name, _ := e.key.text()
+			field, ok := fieldByName(fields, name)
+			if !ok {
+				return fmt.Errorf("can't unmarshal unknown field %s of %s", joinPath(path, name), t)
+			}
+			if err := e.value.assign(v.Field(field.index), joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return n.errorf(path, t)
+}
+
+func fieldByName(fields []structField, name string) (structField, bool) {
+	for _, f := range fields {
+		if f.name == name && !f.anonymous {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
+
+// listElems returns the elements of a listNode
+// without truncation ellipsis
+func (n *node) listElems() []*node {
+	elems := make([]*node, 0, len(n.elems))
+	for _, elem := range n.elems {
+		if !elem.isTruncation() {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+// generic returns the value of n for an empty interface
+func (n *node) generic() any {
+	switch n.kind {
+	case quotedNode:
+		return n.str
+	case scalarNode:
+		if n.isNil() {
+			return nil
+		}
+		switch n.name {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+		if i, err := strconv.Atoi(n.name); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(n.name, 64); err == nil {
+			return f
+		}
+		return n.name
+	case compositeNode:
+		m := make(map[string]any, len(n.entries))
+		for _, e := range n.entries {
+			if e.value.isTruncation() {
+				continue
+			}
+			if e.key == nil {
+				// Merge embedded struct fields
+				if embedded, ok := e.value.generic().(map[string]any); ok {
+					for key, val := range embedded {
+						m[key] = val
+					}
+				}
+				continue
+			}
+			key, _ := e.key.text()
+			m[key] = e.value.generic()
+		}
+		return m
+	case listNode:
+		elems := n.listElems()
+		s := make([]any, len(elems))
+		for i, elem := range elems {
+			s[i] = elem.generic()
+		}
+		return s
+	case callNode:
+		if num, ok := n.number(); ok && num == n.name {
+			return (&node{kind: scalarNode, name: num}).generic()
+		}
+		if n.arg != nil {
+			return n.arg.generic()
+		}
+	}
+	return nil
+}
+
+func (n *node) errorf(path string, t reflect.Type) error {
+	var printed string
+	switch n.kind {
+	case quotedNode:
+		printed = strconv.Quote(n.str)
+	case scalarNode:
+		printed = n.name
+	case compositeNode:
+		printed = n.name + "{…}"
+	case listNode:
+		printed = "[…]"
+	case callNode:
+		printed = n.name + "(…)"
+	}
+	return fmt.Errorf("can't unmarshal %s into %s of %s", printed, pathOrValue(path), t)
+}
+
+func pathOrValue(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
+
+// parser parses the pretty format into nodes
+type parser struct {
+	data     string
+	pos      int
+	ellipsis string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("can't parse pretty format at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		r, size := utf8.DecodeRuneInString(p.data[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// peek returns the next byte after whitespace or 0 at the end
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos == len(p.data) {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte("{}[]():;,`\"", c) >= 0
+}
+
+func (p *parser) parseValue() (*node, error) {
+	switch c := p.peek(); c {
+	case 0:
+		return nil, p.errorf("unexpected end")
+	case '{':
+		return p.parseComposite("")
+	case '[':
+		return p.parseList()
+	case '`', '"':
+		return p.parseQuoted()
+	default:
+		if isDelimiter(c) {
+			return nil, p.errorf("unexpected %q", c)
+		}
+	}
+	start := p.pos
+	for p.pos < len(p.data) && !isDelimiter(p.data[p.pos]) {
+		r, size := utf8.DecodeRuneInString(p.data[p.pos:])
+		if unicode.IsSpace(r) {
+			break
+		}
+		p.pos += size
+	}
+	name := p.data[start:p.pos]
+	truncation := strings.HasPrefix(name, p.ellipsis)
+	if p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case '{':
+			return p.parseComposite(name)
+		case '(':
+			n, err := p.parseCall(name)
+			if n != nil {
+				n.truncation = truncation
+			}
+			return n, err
+		}
+	}
+	return &node{kind: scalarNode, name: name, truncation: truncation}, nil
+}
+
+func (p *parser) parseComposite(name string) (*node, error) {
+	n := &node{kind: compositeNode, name: name}
+	p.pos++ // {
+	for {
+		switch p.peek() {
+		case 0:
+			return nil, p.errorf("missing }")
+		case '}':
+			p.pos++
+			return n, nil
+		case ';', ',':
+			p.pos++
+			continue
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if value.isTruncation() {
+			// Skip the rest of a truncation like …+3 more
+			p.skipTo("};")
+			n.entries = append(n.entries, nodeEntry{value: value})
+			continue
+		}
+		if p.peek() != ':' {
+			n.entries = append(n.entries, nodeEntry{value: value})
+			continue
+		}
+		p.pos++ // :
+		key := value
+		if value, err = p.parseValue(); err != nil {
+			return nil, err
+		}
+		n.entries = append(n.entries, nodeEntry{key: key, value: value})
+	}
+}
+
+func (p *parser) parseList() (*node, error) {
+	n := &node{kind: listNode}
+	p.pos++ // [
+	for {
+		switch p.peek() {
+		case 0:
+			return nil, p.errorf("missing ]")
+		case ']':
+			p.pos++
+			return n, nil
+		case ',':
+			p.pos++
+			continue
+		}
+		elem, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		n.elems = append(n.elems, elem)
+	}
+}
+
+func (p *parser) parseCall(name string) (*node, error) {
+	n := &node{kind: callNode, name: name}
+	p.pos++ // (
+	if p.peek() == ')' {
+		p.pos++
+		return n, nil
+	}
+	arg, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	n.arg = arg
+	// Skip everything after the first argument
+	// like in …(+3 fields)
+	p.skipTo(")")
+	if p.pos == len(p.data) {
+		return nil, p.errorf("missing )")
+	}
+	p.pos++ // )
+	return n, nil
+}
+
+// skipTo advances to the next byte of chars
+// that is not nested in brackets or quotes
+func (p *parser) skipTo(chars string) {
+	depth := 0
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		switch {
+		case depth == 0 && strings.IndexByte(chars, c) >= 0:
+			return
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			depth--
+		case c == '`' || c == '"':
+			if _, err := p.parseQuoted(); err != nil {
+				return
+			}
+			continue
+		}
+		p.pos++
+	}
+}
+
+// parseQuoted parses a string quoted by quoteString.
+// Strings that can't be backquoted are printed
+// with Go escape sequences between backticks,
+// so backslash escapes are interpreted if valid.
+// Because backticks within the string are not escaped,
+// a backtick only ends the string if it is followed
+// by a delimiter or the end of the data.
+func (p *parser) parseQuoted() (*node, error) {
+	quote := p.data[p.pos]
+	start := p.pos + 1
+	for i := start; i < len(p.data); i++ {
+		c := p.data[i]
+		if quote == '"' && c == '\\' {
+			i++
+			continue
+		}
+		if c != quote || !p.endsQuote(i+1) {
+			continue
+		}
+		p.pos = i + 1
+		str := p.data[start:i]
+		if quote == '"' || strings.IndexByte(str, '\\') >= 0 {
+			if unquoted, err := strconv.Unquote(`"` + str + `"`); err == nil {
+				str = unquoted
+			}
+		}
+		return &node{kind: quotedNode, str: str}, nil
+	}
+	return nil, p.errorf("missing closing %c", quote)
+}
+
+// endsQuote returns if the data at pos can follow a closing quote
+func (p *parser) endsQuote(pos int) bool {
+	for pos < len(p.data) {
+		r, size := utf8.DecodeRuneInString(p.data[pos:])
+		switch {
+		case r == '\n':
+			// Indented output has no newlines within strings
+			return true
+		case unicode.IsSpace(r):
+			pos += size
+		case strings.HasPrefix(p.data[pos:], p.ellipsis):
+			return true
+		default:
+			return strings.ContainsRune("}]):;,", r)
+		}
+	}
+	return true
+}
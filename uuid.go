@@ -0,0 +1,70 @@
+package pretty
+
+import (
+	"encoding/hex"
+	"reflect"
+)
+
+// isUUIDArrayType returns if t is a 16 byte array type
+// like the UUID types of the common UUID packages.
+func isUUIDArrayType(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8
+}
+
+// uuidMethod returns the UUID method of v or its address
+// if it takes no arguments and returns a 16 byte array,
+// else an invalid reflect.Value.
+func uuidMethod(v reflect.Value) reflect.Value {
+	if !v.CanInterface() {
+		return reflect.Value{}
+	}
+	m := v.MethodByName("UUID")
+	if !m.IsValid() && v.CanAddr() {
+		m = v.Addr().MethodByName("UUID")
+	}
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || !isUUIDArrayType(m.Type().Out(0)) {
+		return reflect.Value{}
+	}
+	return m
+}
+
+// isUUID returns if v is printed as UUID
+// with Printer.DetectUUIDs.
+func isUUID(v reflect.Value) bool {
+	if isUUIDArrayType(v.Type()) {
+		return true
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return false
+	}
+	return uuidMethod(v).IsValid()
+}
+
+// uuidString returns the canonical string form
+// like 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+// of v if v is a 16 byte array or has a UUID method
+// returning one.
+func uuidString(v reflect.Value) (string, bool) {
+	if !isUUIDArrayType(v.Type()) {
+		m := uuidMethod(v)
+		if !m.IsValid() {
+			return "", false
+		}
+		v = m.Call(nil)[0]
+	}
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(v.Index(i).Uint())
+	}
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:]), true
+}
@@ -0,0 +1,59 @@
+package pretty
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// Watcher prints a value that is polled repeatedly,
+// like a status struct in a CLI loop or debug endpoint,
+// in full the first time and afterwards only its changed paths.
+// Safe for concurrent use.
+type Watcher struct {
+	printer *Printer
+	mutex   sync.Mutex
+	paths   map[string]pathValue
+}
+
+// NewWatcher returns a Watcher using printer
+// or the default Printer if printer is nil.
+func NewWatcher(printer *Printer) *Watcher {
+	if printer == nil {
+		printer = Default()
+	}
+	return &Watcher{printer: printer}
+}
+
+// Print prints value to os.Stdout, see Watcher.Fprint.
+func (w *Watcher) Print(value any) {
+	w.Fprint(os.Stdout, value)
+}
+
+// Fprint prints value followed by a newline to out on the first call
+// and after Reset. Following calls print only the differences
+// to the value of the previous call in the format of DiffJSON,
+// like "~ Status: `starting` → `running`",
+// and nothing if there are no differences.
+//
+// #nosec G104 -- We don't check for errors writing to out
+func (w *Watcher) Fprint(out io.Writer, value any) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	paths := w.printer.unlimitedPaths(value)
+	if w.paths == nil {
+		w.printer.Fprintln(out, value)
+	} else if diff := formatPathDiff(w.paths, paths, w.printer.arrow()); diff != "" {
+		io.WriteString(out, diff)
+	}
+	w.paths = paths
+}
+
+// Reset makes the next call of Print or Fprint
+// print the full value again.
+func (w *Watcher) Reset() {
+	w.mutex.Lock()
+	w.paths = nil
+	w.mutex.Unlock()
+}